@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
@@ -12,21 +13,50 @@ Adapter - adapter intarface for DataServices
 */
 type Adapter interface {
 	Connect() error
-	Exec(string) (sql.Result, error)
-	QueryRow(string) (*sql.Row, error)
-	Query(...interface{}) (*sql.Rows, error)
+	Close() error
+	Exec(string, ...interface{}) (sql.Result, error)
+	QueryRow(string, ...interface{}) (*sql.Row, error)
+	Query(string, ...interface{}) (*sql.Rows, error)
+	// ExecContext, QueryRowContext, QueryContext - queries that are cancelled when context is done
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	QueryRowContext(context.Context, string, ...interface{}) (*sql.Row, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
 	Builder() builders.Builder
 }
 
+/*
+TxAdapter - SQL adapter that can start transactions
+*/
+type TxAdapter interface {
+	Adapter
+	Begin() (Tx, error)
+	// BeginTx - starting transaction that is rolled back when context is done
+	BeginTx(context.Context, *sql.TxOptions) (Tx, error)
+}
+
+/*
+Tx - Adapter bound to transaction. Can be passed to repositories instead of Adapter
+*/
+type Tx interface {
+	Adapter
+	Commit() error
+	Rollback() error
+}
+
 /*
 KVAdapter - Key/value adapter intarface for DataServices
 */
 type KVAdapter interface {
 	Connect() error
+	Close() error
 	Get(key string) ([]byte, error)
 	Set(key string, value interface{}, expiry time.Duration) error
 	SetJSON(key string, value interface{}, expiry time.Duration) error
 	Del(key string) error
+	GetContext(ctx context.Context, key string) ([]byte, error)
+	SetContext(ctx context.Context, key string, value interface{}, expiry time.Duration) error
+	SetJSONContext(ctx context.Context, key string, value interface{}, expiry time.Duration) error
+	DelContext(ctx context.Context, key string) error
 }
 
 /*
@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -38,6 +39,15 @@ func (db *MySQL) Connect() error {
 	return db.connect()
 }
 
+/*
+Close - closing connection. Adapter will connect again on next query
+*/
+func (db *MySQL) Close() error {
+	err := db.closeConnection()
+	db.conn = nil
+	return err
+}
+
 /*
 Builder - returns Query builder (SQL) instance
 */
@@ -48,15 +58,22 @@ func (db MySQL) Builder() builders.Builder {
 /*
 Exec - executing SQL-query and returning *Rows
 */
-func (db *MySQL) Exec(SQL string) (res sql.Result, err error) {
+func (db *MySQL) Exec(SQL string, args ...interface{}) (res sql.Result, err error) {
+	return db.ExecContext(context.Background(), SQL, args...)
+}
+
+/*
+ExecContext - executing SQL-query that is cancelled when ctx is done
+*/
+func (db *MySQL) ExecContext(ctx context.Context, SQL string, args ...interface{}) (res sql.Result, err error) {
 	if err = db.checkConnection(); err != nil {
 		return
 	}
-	res, err = db.conn.Exec(SQL)
+	res, err = db.conn.ExecContext(ctx, SQL, args...)
 	if err != nil {
 		if isInvalidConnection(err) {
 			db.closeConnection()
-			return db.Exec(SQL)
+			return db.ExecContext(ctx, SQL, args...)
 		}
 	}
 	return
@@ -65,17 +82,22 @@ func (db *MySQL) Exec(SQL string) (res sql.Result, err error) {
 /*
 Query - preparing query into Statement and executing SQL-query and returning *Rows
 */
-func (db *MySQL) Query(v ...interface{}) (rows *sql.Rows, err error) {
+func (db *MySQL) Query(SQL string, args ...interface{}) (rows *sql.Rows, err error) {
+	return db.QueryContext(context.Background(), SQL, args...)
+}
+
+/*
+QueryContext - executing SQL-query that is cancelled when ctx is done and returning *Rows
+*/
+func (db *MySQL) QueryContext(ctx context.Context, SQL string, args ...interface{}) (rows *sql.Rows, err error) {
 	if err = db.checkConnection(); err != nil {
 		return
 	}
-	SQL := v[0].(string)
-	values := v[1:]
-	rows, err = db.conn.Query(SQL, values...)
+	rows, err = db.conn.QueryContext(ctx, SQL, args...)
 	if err != nil {
 		if isInvalidConnection(err) {
 			db.closeConnection()
-			return db.Query(v...)
+			return db.QueryContext(ctx, SQL, args...)
 		}
 	}
 	return
@@ -84,18 +106,42 @@ func (db *MySQL) Query(v ...interface{}) (rows *sql.Rows, err error) {
 /*
 QueryRow - executing single row query. May be suitable for INSERT/UPDATE.
 */
-func (db *MySQL) QueryRow(SQL string) (row *sql.Row, err error) {
+func (db *MySQL) QueryRow(SQL string, args ...interface{}) (row *sql.Row, err error) {
+	return db.QueryRowContext(context.Background(), SQL, args...)
+}
+
+/*
+QueryRowContext - executing single row query that is cancelled when ctx is done
+*/
+func (db *MySQL) QueryRowContext(ctx context.Context, SQL string, args ...interface{}) (row *sql.Row, err error) {
 	if err := db.checkConnection(); err != nil {
 		return nil, err
 	}
-	row, err = db.conn.QueryRow(SQL), nil
+	return db.conn.QueryRowContext(ctx, SQL, args...), nil
+}
+
+/*
+Begin - starting transaction. Returned Tx can be used as Adapter until Commit or Rollback
+*/
+func (db *MySQL) Begin() (Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+/*
+BeginTx - starting transaction with options. Transaction is rolled back when ctx is done
+*/
+func (db *MySQL) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	if err := db.checkConnection(); err != nil {
+		return nil, err
+	}
+	tx, err := db.conn.BeginTx(ctx, opts)
 	if err != nil {
-		if isInvalidConnection(err) {
-			db.closeConnection()
-			return db.QueryRow(SQL)
-		}
+		return nil, err
 	}
-	return
+	return &Transaction{
+		tx:      tx,
+		builder: builders.NewMySQL,
+	}, nil
 }
 
 func isInvalidConnection(err error) bool {
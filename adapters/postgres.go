@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -38,6 +39,18 @@ func (psql *Postgres) Connect() error {
 	return psql.connect()
 }
 
+/*
+Close - closing connection. Adapter will connect again on next query
+*/
+func (psql *Postgres) Close() error {
+	if psql.conn == nil {
+		return nil
+	}
+	err := psql.conn.Close()
+	psql.conn = nil
+	return err
+}
+
 /*
 Builder - returns Query builder (SQL) instance
 */
@@ -48,33 +61,76 @@ func (psql Postgres) Builder() builders.Builder {
 /*
 Exec - executing SQL-query and returning Result
 */
-func (psql *Postgres) Exec(SQL string) (sql.Result, error) {
+func (psql *Postgres) Exec(SQL string, args ...interface{}) (sql.Result, error) {
+	return psql.ExecContext(context.Background(), SQL, args...)
+}
+
+/*
+ExecContext - executing SQL-query that is cancelled when ctx is done
+*/
+func (psql *Postgres) ExecContext(ctx context.Context, SQL string, args ...interface{}) (sql.Result, error) {
 	if err := psql.checkConnection(); err != nil {
 		return nil, err
 	}
-	return psql.conn.Exec(SQL)
+	return psql.conn.ExecContext(ctx, SQL, args...)
 }
 
 /*
 Query - preparing query into Statement and executing SQL-query and returning *Rows
 */
-func (psql *Postgres) Query(v ...interface{}) (*sql.Rows, error) {
+func (psql *Postgres) Query(SQL string, args ...interface{}) (*sql.Rows, error) {
+	return psql.QueryContext(context.Background(), SQL, args...)
+}
+
+/*
+QueryContext - executing SQL-query that is cancelled when ctx is done and returning *Rows
+*/
+func (psql *Postgres) QueryContext(ctx context.Context, SQL string, args ...interface{}) (*sql.Rows, error) {
 	if err := psql.checkConnection(); err != nil {
 		return nil, err
 	}
-	SQL := v[0].(string)
-	values := v[1:]
-	return psql.conn.Query(SQL, values...)
+	return psql.conn.QueryContext(ctx, SQL, args...)
 }
 
 /*
 QueryRow - executing single row query. May be suitable for INSERT/UPDATE.
 */
-func (psql *Postgres) QueryRow(SQL string) (*sql.Row, error) {
+func (psql *Postgres) QueryRow(SQL string, args ...interface{}) (*sql.Row, error) {
+	return psql.QueryRowContext(context.Background(), SQL, args...)
+}
+
+/*
+QueryRowContext - executing single row query that is cancelled when ctx is done
+*/
+func (psql *Postgres) QueryRowContext(ctx context.Context, SQL string, args ...interface{}) (*sql.Row, error) {
+	if err := psql.checkConnection(); err != nil {
+		return nil, err
+	}
+	return psql.conn.QueryRowContext(ctx, SQL, args...), nil
+}
+
+/*
+Begin - starting transaction. Returned Tx can be used as Adapter until Commit or Rollback
+*/
+func (psql *Postgres) Begin() (Tx, error) {
+	return psql.BeginTx(context.Background(), nil)
+}
+
+/*
+BeginTx - starting transaction with options. Transaction is rolled back when ctx is done
+*/
+func (psql *Postgres) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
 	if err := psql.checkConnection(); err != nil {
 		return nil, err
 	}
-	return psql.conn.QueryRow(SQL), nil
+	tx, err := psql.conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		tx:      tx,
+		builder: builders.NewPostgres,
+	}, nil
 }
 
 func (psql *Postgres) connect() error {
@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -29,49 +30,87 @@ func (r *Redis) Connect() error {
 	return r.connect()
 }
 
+// Close - closing client. Adapter will connect again on next command
+func (r *Redis) Close() error {
+	if r.client == nil {
+		return nil
+	}
+	err := r.client.Close()
+	r.client = nil
+	return err
+}
+
 /*
 Get - getting data by key
 */
 func (r *Redis) Get(key string) ([]byte, error) {
-	err := r.checkConnection()
+	return r.GetContext(context.Background(), key)
+}
+
+/*
+GetContext - getting data by key. Command is cancelled when ctx is done
+*/
+func (r *Redis) GetContext(ctx context.Context, key string) ([]byte, error) {
+	err := r.checkContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 	// TODO: check for redis.Nil: if err == redis.Nil {
-	return r.client.Get(key).Bytes()
+	return r.client.WithContext(ctx).Get(key).Bytes()
 }
 
 /*
 Del - deleting data by key
 */
 func (r *Redis) Del(key string) error {
-	err := r.checkConnection()
+	return r.DelContext(context.Background(), key)
+}
+
+/*
+DelContext - deleting data by key. Command is cancelled when ctx is done
+*/
+func (r *Redis) DelContext(ctx context.Context, key string) error {
+	err := r.checkContext(ctx)
 	if err != nil {
 		return err
 	}
-	return r.client.Del(key).Err()
+	return r.client.WithContext(ctx).Del(key).Err()
 }
 
 /*
 Set - setting key with value and expiration time
 */
 func (r *Redis) Set(key string, value interface{}, exp time.Duration) error {
-	err := r.checkConnection()
+	return r.SetContext(context.Background(), key, value, exp)
+}
+
+/*
+SetContext - setting key with value and expiration time. Command is cancelled when ctx is done
+*/
+func (r *Redis) SetContext(ctx context.Context, key string, value interface{}, exp time.Duration) error {
+	err := r.checkContext(ctx)
 	if err != nil {
 		return err
 	}
-	return r.client.Set(key, value, exp).Err()
+	return r.client.WithContext(ctx).Set(key, value, exp).Err()
 }
 
 /*
 SetJSON - setting key with value and expiration time that will be saved as JSON
 */
 func (r *Redis) SetJSON(key string, value interface{}, exp time.Duration) error {
+	return r.SetJSONContext(context.Background(), key, value, exp)
+}
+
+/*
+SetJSONContext - setting key with value that will be saved as JSON. Command is cancelled when ctx is done
+*/
+func (r *Redis) SetJSONContext(ctx context.Context, key string, value interface{}, exp time.Duration) error {
 	str, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
-	return r.Set(key, str, exp)
+	return r.SetContext(ctx, key, str, exp)
 }
 
 func (r *Redis) connect() error {
@@ -84,6 +123,15 @@ func (r *Redis) connect() error {
 	return err
 }
 
+// checkContext - go-redis v6 keeps the context on the client but doesn't
+// interrupt commands with it, so a done context is checked before sending
+func (r *Redis) checkContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.checkConnection()
+}
+
 func (r *Redis) checkConnection() error {
 	if r.client == nil {
 		return r.connect()
@@ -0,0 +1,26 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRedisCancelledContext(t *testing.T) {
+	r := NewRedis(Config{Host: "127.0.0.1", Port: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := r.GetContext(ctx, "key"); err != context.Canceled {
+		t.Errorf("get: expected context.Canceled, got %v", err)
+	}
+	if err := r.SetContext(ctx, "key", "value", time.Second); err != context.Canceled {
+		t.Errorf("set: expected context.Canceled, got %v", err)
+	}
+	if err := r.DelContext(ctx, "key"); err != context.Canceled {
+		t.Errorf("del: expected context.Canceled, got %v", err)
+	}
+	if r.client != nil {
+		t.Error("adapter connected for a cancelled context")
+	}
+}
@@ -0,0 +1,133 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/syndicatedb/vodka/builders"
+)
+
+/*
+Transaction - SQL adapter bound to database transaction
+*/
+type Transaction struct {
+	tx      *sql.Tx
+	builder func() builders.Builder
+}
+
+/*
+Connect - transaction is already connected. Returns error if transaction is not started
+*/
+func (t *Transaction) Connect() error {
+	if t.tx == nil {
+		return errors.New("transaction_not_started")
+	}
+	return nil
+}
+
+/*
+Close - rolling back transaction if it wasn't committed
+*/
+func (t *Transaction) Close() error {
+	if err := t.tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		return err
+	}
+	return nil
+}
+
+/*
+Builder - returns Query builder (SQL) instance of adapter that started transaction
+*/
+func (t *Transaction) Builder() builders.Builder {
+	return t.builder()
+}
+
+/*
+Exec - executing SQL-query in transaction and returning Result
+*/
+func (t *Transaction) Exec(SQL string, args ...interface{}) (sql.Result, error) {
+	return t.tx.Exec(SQL, args...)
+}
+
+/*
+ExecContext - executing SQL-query in transaction that is cancelled when ctx is done
+*/
+func (t *Transaction) ExecContext(ctx context.Context, SQL string, args ...interface{}) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, SQL, args...)
+}
+
+/*
+Query - executing SQL-query in transaction and returning *Rows
+*/
+func (t *Transaction) Query(SQL string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.Query(SQL, args...)
+}
+
+/*
+QueryContext - executing SQL-query in transaction that is cancelled when ctx is done
+*/
+func (t *Transaction) QueryContext(ctx context.Context, SQL string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, SQL, args...)
+}
+
+/*
+QueryRow - executing single row query in transaction
+*/
+func (t *Transaction) QueryRow(SQL string, args ...interface{}) (*sql.Row, error) {
+	return t.tx.QueryRow(SQL, args...), nil
+}
+
+/*
+QueryRowContext - executing single row query in transaction that is cancelled when ctx is done
+*/
+func (t *Transaction) QueryRowContext(ctx context.Context, SQL string, args ...interface{}) (*sql.Row, error) {
+	return t.tx.QueryRowContext(ctx, SQL, args...), nil
+}
+
+/*
+Commit - committing transaction
+*/
+func (t *Transaction) Commit() error {
+	return t.tx.Commit()
+}
+
+/*
+Rollback - aborting transaction
+*/
+func (t *Transaction) Rollback() error {
+	return t.tx.Rollback()
+}
+
+/*
+WithTx - running fn in transaction started on adapter.
+Transaction is rolled back if fn returns error or panics (panic is raised again after rollback),
+otherwise transaction is committed. Error of commit is driver error,
+base.Transaction translates it as repositories do
+*/
+func WithTx(adapter TxAdapter, fn func(Tx) error) (err error) {
+	return WithTxContext(context.Background(), adapter, fn)
+}
+
+/*
+WithTxContext - same as WithTx but transaction is rolled back when ctx is done
+*/
+func WithTxContext(ctx context.Context, adapter TxAdapter, fn func(Tx) error) (err error) {
+	tx, err := adapter.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	err = fn(tx)
+	return
+}
@@ -0,0 +1,98 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// testTx - transaction that counts commits and rollbacks
+type testTx struct {
+	Adapter
+	commits   int
+	rollbacks int
+	commitErr error
+}
+
+func (tx *testTx) Commit() error {
+	tx.commits++
+	return tx.commitErr
+}
+
+func (tx *testTx) Rollback() error {
+	tx.rollbacks++
+	return nil
+}
+
+// testTxAdapter - adapter that starts testTx
+type testTxAdapter struct {
+	Adapter
+	tx       *testTx
+	beginErr error
+}
+
+func (a *testTxAdapter) Begin() (Tx, error) {
+	return a.BeginTx(context.Background(), nil)
+}
+
+func (a *testTxAdapter) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	if a.beginErr != nil {
+		return nil, a.beginErr
+	}
+	return a.tx, nil
+}
+
+func TestWithTxCommits(t *testing.T) {
+	adapter := &testTxAdapter{tx: &testTx{}}
+	var got Tx
+	err := WithTx(adapter, func(tx Tx) error {
+		got = tx
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != adapter.tx {
+		t.Errorf("function got other transaction")
+	}
+	if adapter.tx.commits != 1 || adapter.tx.rollbacks != 0 {
+		t.Errorf("expected commit, got %d commits and %d rollbacks", adapter.tx.commits, adapter.tx.rollbacks)
+	}
+}
+
+func TestWithTxRollsBackOnError(t *testing.T) {
+	adapter := &testTxAdapter{tx: &testTx{}}
+	errFailed := errors.New("failed")
+	if err := WithTx(adapter, func(Tx) error { return errFailed }); err != errFailed {
+		t.Fatalf("expected error of function, got %v", err)
+	}
+	if adapter.tx.commits != 0 || adapter.tx.rollbacks != 1 {
+		t.Errorf("expected rollback, got %d commits and %d rollbacks", adapter.tx.commits, adapter.tx.rollbacks)
+	}
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	adapter := &testTxAdapter{tx: &testTx{}}
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("panic is not raised again: %v", p)
+		}
+		if adapter.tx.commits != 0 || adapter.tx.rollbacks != 1 {
+			t.Errorf("expected rollback, got %d commits and %d rollbacks", adapter.tx.commits, adapter.tx.rollbacks)
+		}
+	}()
+	WithTx(adapter, func(Tx) error { panic("boom") })
+}
+
+func TestWithTxReturnsErrors(t *testing.T) {
+	errBegin := errors.New("begin")
+	if err := WithTx(&testTxAdapter{beginErr: errBegin}, func(Tx) error { return nil }); err != errBegin {
+		t.Errorf("expected begin error, got %v", err)
+	}
+	errCommit := errors.New("commit")
+	adapter := &testTxAdapter{tx: &testTx{commitErr: errCommit}}
+	if err := WithTx(adapter, func(Tx) error { return nil }); err != errCommit {
+		t.Errorf("expected commit error, got %v", err)
+	}
+}
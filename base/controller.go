@@ -1,6 +1,9 @@
 package base
 
 import (
+	"context"
+	"log"
+
 	"github.com/syndicatedb/vodka"
 )
 
@@ -8,7 +11,7 @@ import (
 Controller - base controller interface that describes base CRUD methods
 */
 type Controller interface {
-	// Find — finding in DB rows. Returns array
+	// Find — finding in DB rows. Returns array or Page if route has option paginate
 	Find(*vodka.Context) (interface{}, error)
 	// FindByID - finding item in DB by key defined in model
 	FindByID(*vodka.Context) (interface{}, error)
@@ -29,15 +32,56 @@ type ctrl struct {
 	Service
 }
 
-// NewController - controller constructor
+/*
+NewController - controller constructor. Service that is not ContextBinder and is not created
+with NewService is used as is, so its queries are not cancelled with request: it is logged once here
+*/
 func NewController(srv Service) Controller {
+	if !bindsContext(srv) {
+		log.Printf("Controller service %T is not base.ContextBinder: queries are not cancelled with request", srv)
+	}
 	return &ctrl{
 		Service: srv,
 	}
 }
 
+/*
+ContextBinder - service that binds itself to context of request. Modules that embed Service
+implement it, so base.Controller keeps their overrides:
+
+	func (a *API) BindContext(ctx context.Context) base.Service {
+		return &API{Service: a.Service.WithContext(ctx)}
+	}
+*/
+type ContextBinder interface {
+	BindContext(context.Context) Service
+}
+
+/*
+service - service bound to context of request, so queries are cancelled with request.
+Service of other type that is not ContextBinder is used as is: its WithContext may be
+promoted from embedded Service and would drop overrides
+*/
+func (c *ctrl) service(ctx *vodka.Context) Service {
+	switch s := c.Service.(type) {
+	case ContextBinder:
+		return s.BindContext(ctx.Context())
+	case *service:
+		return s.WithContext(ctx.Context())
+	}
+	return c.Service
+}
+
+func bindsContext(srv Service) bool {
+	switch srv.(type) {
+	case ContextBinder, *service:
+		return true
+	}
+	return false
+}
+
 func (c *ctrl) FindByID(ctx *vodka.Context) (interface{}, error) {
-	return c.Service.FindByID(ctx.Params.Get("id"))
+	return c.service(ctx).FindByID(ctx.Params.Get("id"))
 }
 
 func (c *ctrl) Find(ctx *vodka.Context) (interface{}, error) {
@@ -45,11 +89,36 @@ func (c *ctrl) Find(ctx *vodka.Context) (interface{}, error) {
 	if p, ok := ctx.Options.Get("params").(map[string]interface{}); ok {
 		params = p
 	}
-	return c.Service.Find(ctx.Query.Map(), params)
+	srv := c.service(ctx)
+	mode, _ := ctx.Options.Get("paginate").(string)
+	if mode == PaginateCursor {
+		items, cursor, err := srv.FindAfter(ctx.Query.Map(), params)
+		if err != nil {
+			return items, err
+		}
+		return newCursorPage(ctx, items, cursor, params), nil
+	}
+	items, err := srv.Find(ctx.Query.Map(), params)
+	if err != nil {
+		return items, err
+	}
+	if mode != PaginateBody && mode != PaginateHeaders {
+		return items, nil
+	}
+	total, err := srv.Count(ctx.Query.Map())
+	if err != nil {
+		return nil, err
+	}
+	page := newPage(ctx, items, total, params)
+	if mode == PaginateHeaders {
+		page.setHeaders(ctx)
+		return items, nil
+	}
+	return page, nil
 }
 
 func (c *ctrl) Create(ctx *vodka.Context) (interface{}, error) {
-	return c.Service.Create(ctx.Body.Map())
+	return c.service(ctx).Create(ctx.Body.Map())
 }
 
 func (c *ctrl) Save(ctx *vodka.Context) (interface{}, error) {
@@ -57,15 +126,15 @@ func (c *ctrl) Save(ctx *vodka.Context) (interface{}, error) {
 	if p, ok := ctx.Options.Get("params").(map[string]interface{}); ok {
 		params = p
 	}
-	return c.Service.Save(ctx.Body.Map(), params)
+	return c.service(ctx).Save(ctx.Body.Map(), params)
 }
 
 func (c *ctrl) Update(ctx *vodka.Context) (interface{}, error) {
-	return c.Service.Update(ctx.Query.Map(), ctx.Body.Map())
+	return c.service(ctx).Update(ctx.Query.Map(), ctx.Body.Map())
 }
 
 func (c *ctrl) UpdateByID(ctx *vodka.Context) (interface{}, error) {
-	items, err := c.Service.Update(ctx.Params.Map(), ctx.Body.Map())
+	items, err := c.service(ctx).Update(ctx.Params.Map(), ctx.Body.Map())
 	if err != nil {
 		return items, err
 	}
@@ -79,7 +148,11 @@ func (c *ctrl) UpdateByID(ctx *vodka.Context) (interface{}, error) {
 }
 
 func (c *ctrl) DeleteByID(ctx *vodka.Context) (interface{}, error) {
-	res, err := c.Service.DeleteByID(ctx.Params.GetString("id"))
+	id, ok := ctx.Params.Lookup("id")
+	if !ok {
+		return nil, vodka.NewBadRequestError("id is not defined", nil)
+	}
+	res, err := c.service(ctx).DeleteByID(id)
 	if err != nil {
 		return res, err
 	}
@@ -0,0 +1,225 @@
+package base
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/syndicatedb/vodka"
+	"github.com/syndicatedb/vodka/adapters"
+)
+
+// testService - service that records calls of controller
+type testService struct {
+	items  interface{}
+	total  int64
+	cursor string
+	query  map[string]interface{}
+	params map[string]interface{}
+	ctx    context.Context
+}
+
+func (s *testService) Find(query, params map[string]interface{}) (interface{}, error) {
+	s.query, s.params = query, params
+	return s.items, nil
+}
+
+func (s *testService) FindByID(id interface{}) (interface{}, error) {
+	return id, nil
+}
+
+func (s *testService) FindAfter(query, params map[string]interface{}) (interface{}, string, error) {
+	s.query, s.params = query, params
+	return s.items, s.cursor, nil
+}
+
+func (s *testService) Count(query map[string]interface{}) (int64, error) {
+	return s.total, nil
+}
+
+func (s *testService) Create(payload interface{}) (interface{}, error) {
+	return payload, nil
+}
+
+func (s *testService) Save(payload, params map[string]interface{}) (interface{}, error) {
+	return payload, nil
+}
+
+func (s *testService) Update(query, payload map[string]interface{}) (interface{}, error) {
+	return []interface{}{payload}, nil
+}
+
+func (s *testService) DeleteByID(id interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func (s *testService) WithTx(adapters.Tx) Service {
+	return s
+}
+
+func (s *testService) WithContext(ctx context.Context) Service {
+	s.ctx = ctx
+	return s
+}
+
+// newTestContext - context of GET request with route option paginate and query params
+func newTestContext(target, paginate string, params map[string]interface{}) *vodka.Context {
+	ctx := &vodka.Context{
+		Request: httptest.NewRequest("GET", target, nil),
+		Writer:  httptest.NewRecorder(),
+	}
+	if paginate != "" {
+		ctx.Options.Set("paginate", paginate)
+	}
+	ctx.Options.Set("params", params)
+	return ctx
+}
+
+func TestFindReturnsItemsWithoutPagination(t *testing.T) {
+	srv := &testService{items: []interface{}{1, 2}}
+	ctx := newTestContext("/users", "", map[string]interface{}{})
+	res, err := NewController(srv).Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(res, srv.items) {
+		t.Errorf("expected items, got %#v", res)
+	}
+}
+
+func TestFindReturnsPage(t *testing.T) {
+	srv := &testService{items: []interface{}{1, 2}, total: 25}
+	ctx := newTestContext("/users?status=new&__limit=10&__skip=10", PaginateBody, map[string]interface{}{"limit": 10, "skip": 10})
+	res, err := NewController(srv).Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Page{
+		Items: srv.items,
+		Total: 25,
+		Limit: 10,
+		Skip:  10,
+		Next:  "/users?__limit=10&__skip=20&status=new",
+		Prev:  "/users?__limit=10&__skip=0&status=new",
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("page:\n got %+v\nwant %+v", res, want)
+	}
+}
+
+func TestFindSetsPageHeaders(t *testing.T) {
+	srv := &testService{items: []interface{}{1, 2}, total: 12}
+	ctx := newTestContext("/users", PaginateHeaders, map[string]interface{}{"limit": 10})
+	res, err := NewController(srv).Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(res, srv.items) {
+		t.Errorf("expected items, got %#v", res)
+	}
+	header := ctx.Writer.Header()
+	if header.Get("X-Total-Count") != "12" {
+		t.Errorf("X-Total-Count: %q", header.Get("X-Total-Count"))
+	}
+	if link := header.Get("Link"); link != `</users?__limit=10&__skip=10>; rel="next"` {
+		t.Errorf("Link: %q", link)
+	}
+}
+
+func TestPageHasNoLinksOnSinglePage(t *testing.T) {
+	ctx := newTestContext("/users", PaginateBody, nil)
+	p := newPage(ctx, nil, 3, nil)
+	if p.Next != "" || p.Prev != "" || p.Limit != 100 {
+		t.Errorf("unexpected page: %+v", p)
+	}
+}
+
+func TestFindReturnsCursorPage(t *testing.T) {
+	srv := &testService{items: []interface{}{1, 2}, cursor: "WzJd"}
+	ctx := newTestContext("/users?__limit=2&__skip=4&__cursor=WzFd", PaginateCursor, map[string]interface{}{"limit": 2})
+	res, err := NewController(srv).Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := CursorPage{Items: srv.items, Limit: 2, Cursor: "WzJd", Next: "/users?__cursor=WzJd&__limit=2"}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("page:\n got %+v\nwant %+v", res, want)
+	}
+
+	// Last page has no cursor and link
+	srv.cursor = ""
+	res, _ = NewController(srv).Find(ctx)
+	if p := res.(CursorPage); p.Cursor != "" || p.Next != "" {
+		t.Errorf("unexpected last page: %+v", p)
+	}
+}
+
+// testModule - module that overrides Create of embedded service
+type testModule struct {
+	Service
+	created *bool
+}
+
+func (m *testModule) Create(payload interface{}) (interface{}, error) {
+	*m.created = true
+	return m.Service.Create(payload)
+}
+
+func (m *testModule) BindContext(ctx context.Context) Service {
+	return &testModule{Service: m.Service.WithContext(ctx), created: m.created}
+}
+
+func TestControllerKeepsOverridesOfContextBinder(t *testing.T) {
+	srv := &testService{}
+	created := false
+	ctx := newTestContext("/users", "", nil)
+
+	if _, err := NewController(&testModule{Service: srv, created: &created}).Create(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created {
+		t.Error("override of module is not called")
+	}
+	if srv.ctx != ctx.Context() {
+		t.Error("service is not bound to context of request")
+	}
+}
+
+func TestControllerUsesServiceAsIs(t *testing.T) {
+	srv := &testService{}
+	ctx := newTestContext("/users", "", nil)
+	if _, err := NewController(srv).Create(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if srv.ctx != nil {
+		t.Error("WithContext of service that is not ContextBinder is called")
+	}
+}
+
+// testPlainModule - module that embeds Service without BindContext
+type testPlainModule struct {
+	Service
+}
+
+func TestControllerReportsServiceWithoutContextBinder(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	NewController(&testPlainModule{Service: &testService{}})
+	if !strings.Contains(buf.String(), "*base.testPlainModule is not base.ContextBinder") {
+		t.Errorf("service without BindContext is not reported: %q", buf.String())
+	}
+
+	buf.Reset()
+	NewController(&testModule{Service: &testService{}})
+	NewController(NewService(nil))
+	if buf.Len() != 0 {
+		t.Errorf("unexpected report: %q", buf.String())
+	}
+}
@@ -0,0 +1,112 @@
+package base
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/syndicatedb/vodka"
+	"github.com/syndicatedb/vodka/repositories"
+)
+
+const (
+	// PaginateBody - route option "paginate" value to respond with Page
+	PaginateBody = "body"
+	// PaginateHeaders - route option "paginate" value to respond with items and X-Total-Count/Link headers
+	PaginateHeaders = "headers"
+	// PaginateCursor - route option "paginate" value to respond with CursorPage (keyset pagination)
+	PaginateCursor = "cursor"
+)
+
+/*
+Page - paginated response of Find
+*/
+type Page struct {
+	Items interface{} `json:"items"`
+	Total int64       `json:"total"`
+	Limit int         `json:"limit"`
+	Skip  int         `json:"skip"`
+	Next  string      `json:"next,omitempty"`
+	Prev  string      `json:"prev,omitempty"`
+}
+
+/*
+CursorPage - keyset paginated response of Find. Cursor is empty on the last page
+*/
+type CursorPage struct {
+	Items  interface{} `json:"items"`
+	Limit  int         `json:"limit"`
+	Cursor string      `json:"cursor,omitempty"`
+	Next   string      `json:"next,omitempty"`
+}
+
+func newCursorPage(ctx *vodka.Context, items interface{}, cursor string, params map[string]interface{}) CursorPage {
+	p := CursorPage{
+		Items:  items,
+		Limit:  repositories.DefaultLimit,
+		Cursor: cursor,
+	}
+	if limit, ok := params["limit"].(int); ok && limit > 0 {
+		p.Limit = limit
+	}
+	if cursor != "" {
+		u := *ctx.Request.URL
+		q := u.Query()
+		q.Set("__cursor", cursor)
+		q.Del("__skip")
+		u.RawQuery = q.Encode()
+		p.Next = u.RequestURI()
+	}
+	return p
+}
+
+func newPage(ctx *vodka.Context, items interface{}, total int64, params map[string]interface{}) Page {
+	p := Page{
+		Items: items,
+		Total: total,
+		Limit: repositories.DefaultLimit,
+	}
+	if limit, ok := params["limit"].(int); ok && limit > 0 {
+		p.Limit = limit
+	}
+	if skip, ok := params["skip"].(int); ok && skip > 0 {
+		p.Skip = skip
+	}
+	if int64(p.Skip+p.Limit) < total {
+		p.Next = pageLink(ctx, p.Limit, p.Skip+p.Limit)
+	}
+	if p.Skip > 0 {
+		prev := p.Skip - p.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		p.Prev = pageLink(ctx, p.Limit, prev)
+	}
+	return p
+}
+
+// setHeaders - setting X-Total-Count and Link (RFC 5988) headers
+func (p Page) setHeaders(ctx *vodka.Context) {
+	header := ctx.Writer.Header()
+	header.Set("X-Total-Count", strconv.FormatInt(p.Total, 10))
+	var links []string
+	if p.Next != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, p.Next))
+	}
+	if p.Prev != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, p.Prev))
+	}
+	if len(links) > 0 {
+		header.Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// pageLink - current request URL with other __limit and __skip
+func pageLink(ctx *vodka.Context, limit, skip int) string {
+	u := *ctx.Request.URL
+	q := u.Query()
+	q.Set("__limit", strconv.Itoa(limit))
+	q.Set("__skip", strconv.Itoa(skip))
+	u.RawQuery = q.Encode()
+	return u.RequestURI()
+}
@@ -1,6 +1,9 @@
 package base
 
 import (
+	"context"
+
+	"github.com/syndicatedb/vodka/adapters"
 	"github.com/syndicatedb/vodka/repositories"
 )
 
@@ -8,10 +11,16 @@ import (
 type Service interface {
 	Find(map[string]interface{}, map[string]interface{}) (interface{}, error)
 	FindByID(interface{}) (interface{}, error)
+	FindAfter(map[string]interface{}, map[string]interface{}) (interface{}, string, error)
+	Count(map[string]interface{}) (int64, error)
 	Create(interface{}) (interface{}, error)
 	Save(map[string]interface{}, map[string]interface{}) (interface{}, error)
 	Update(map[string]interface{}, map[string]interface{}) (interface{}, error)
 	DeleteByID(interface{}) (interface{}, error)
+	// WithTx - copy of service with repository bound to transaction
+	WithTx(adapters.Tx) Service
+	// WithContext - copy of service with repository that cancels queries when context is done
+	WithContext(context.Context) Service
 }
 
 type service struct {
@@ -25,6 +34,43 @@ func NewService(repo repositories.Recorder) Service {
 	}
 }
 
+/*
+Transaction - running fn as one unit of work. Repositories and services used inside fn
+have to be bound to tx with WithTx. Transaction is rolled back if fn returns error or panics
+*/
+func Transaction(adapter adapters.TxAdapter, fn func(tx adapters.Tx) error) error {
+	return TransactionContext(context.Background(), adapter, fn)
+}
+
+/*
+TransactionContext - same as Transaction but transaction is rolled back when ctx is done.
+Errors of begin and commit (serialization failure, deferred constraint) are translated
+with repositories.TranslateError, error of fn is returned as is
+*/
+func TransactionContext(ctx context.Context, adapter adapters.TxAdapter, fn func(tx adapters.Tx) error) error {
+	var fnErr error
+	err := adapters.WithTxContext(ctx, adapter, func(tx adapters.Tx) error {
+		fnErr = fn(tx)
+		return fnErr
+	})
+	if err != nil && fnErr == nil {
+		return repositories.TranslateError(err)
+	}
+	return err
+}
+
+func (s *service) WithTx(tx adapters.Tx) Service {
+	return &service{
+		repository: s.repository.WithTx(tx),
+	}
+}
+
+func (s *service) WithContext(ctx context.Context) Service {
+	return &service{
+		repository: s.repository.WithContext(ctx),
+	}
+}
+
 func (s *service) FindByID(id interface{}) (interface{}, error) {
 	return s.repository.FindByID(id)
 }
@@ -33,6 +79,14 @@ func (s *service) Find(query, params map[string]interface{}) (interface{}, error
 	return s.repository.Find(query, params)
 }
 
+func (s *service) FindAfter(query, params map[string]interface{}) (interface{}, string, error) {
+	return s.repository.FindAfter(query, params)
+}
+
+func (s *service) Count(query map[string]interface{}) (int64, error) {
+	return s.repository.Count(query)
+}
+
 func (s *service) Create(payload interface{}) (interface{}, error) {
 	return s.repository.Create(payload)
 }
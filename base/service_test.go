@@ -0,0 +1,63 @@
+package base
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/syndicatedb/vodka/adapters"
+	"github.com/syndicatedb/vodka/repositories"
+)
+
+// testTx - transaction that fails on commit
+type testTx struct {
+	adapters.Adapter
+	commitErr error
+}
+
+func (tx *testTx) Commit() error   { return tx.commitErr }
+func (tx *testTx) Rollback() error { return nil }
+
+// testTxAdapter - adapter that begins testTx
+type testTxAdapter struct {
+	adapters.Adapter
+	tx       *testTx
+	beginErr error
+}
+
+func (a *testTxAdapter) Begin() (adapters.Tx, error) {
+	return a.BeginTx(context.Background(), nil)
+}
+
+func (a *testTxAdapter) BeginTx(ctx context.Context, opts *sql.TxOptions) (adapters.Tx, error) {
+	if a.beginErr != nil {
+		return nil, a.beginErr
+	}
+	return a.tx, nil
+}
+
+func TestTransactionTranslatesCommitError(t *testing.T) {
+	serialization := &pq.Error{Code: "40001"}
+	adapter := &testTxAdapter{tx: &testTx{commitErr: serialization}}
+	err := Transaction(adapter, func(tx adapters.Tx) error { return nil })
+	if !errors.Is(err, repositories.ErrSerializationFailure) || !errors.Is(err, serialization) {
+		t.Errorf("expected translated commit error, got %v", err)
+	}
+
+	adapter = &testTxAdapter{beginErr: &pq.Error{Code: "40P01"}}
+	err = Transaction(adapter, func(tx adapters.Tx) error { return nil })
+	if !errors.Is(err, repositories.ErrSerializationFailure) {
+		t.Errorf("expected translated begin error, got %v", err)
+	}
+}
+
+func TestTransactionReturnsErrorOfFunction(t *testing.T) {
+	unique := &pq.Error{Code: "23505"}
+	adapter := &testTxAdapter{tx: &testTx{}}
+	err := Transaction(adapter, func(tx adapters.Tx) error { return unique })
+	if err != unique {
+		t.Errorf("error of function is changed: %v", err)
+	}
+}
@@ -0,0 +1,322 @@
+package vodka
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+Struct tags of request types:
+
+	type CreateOrder struct {
+		UserID string  `param:"id" validate:"required,format=uuid"`
+		Notify bool    `query:"notify" validate:"default=false"`
+		Name   string  `json:"name" validate:"required,minLength=2,maxLength=100"`
+		Status string  `json:"status" validate:"enum=new|paid,default=new"`
+		Items  []Item  `json:"items" validate:"required,minLength=1"`
+	}
+
+Fields with `param` tag are taken from path params, with `query` tag — from query,
+other fields — from body by `json` name
+*/
+const (
+	TagParam    = "param"
+	TagQuery    = "query"
+	TagValidate = "validate"
+)
+
+// binding - rules of request type and fields that are filled from validated values
+type binding struct {
+	rules  methodRules
+	fields []boundField
+}
+
+type boundField struct {
+	index    []int
+	location string
+	key      string
+}
+
+// bindings - bindings of request types by reflect.Type
+var bindings sync.Map
+
+/*
+Bind - decoding params, query and body into struct and validating it by struct tags.
+Returns 400 Error with ValidationErrors in Info if request is not valid
+*/
+func (ctx *Context) Bind(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return NewServerError("bind", fmt.Sprintf("destination must be pointer to struct, got %T", dst))
+	}
+	b := requestBinding(v.Elem().Type())
+
+	var errs ValidationErrors
+	params, fieldErrs := validateMap(ctx.types, LocationParams, b.rules.Params, ctx.Raw.Params, UnknownStrip)
+	errs = append(errs, fieldErrs...)
+	query, fieldErrs := validateMap(ctx.types, LocationQuery, b.rules.Query, ctx.Raw.Query, UnknownStrip)
+	errs = append(errs, fieldErrs...)
+	var body KeyStorage
+	if len(b.rules.Body) > 0 {
+		raw, fieldErrs := parseJSONBody(ctx.Raw.Body)
+		errs = append(errs, fieldErrs...)
+		body, fieldErrs = validateMap(ctx.types, LocationBody, b.rules.Body, raw, unknownFieldsPolicy(ctx.Validation.Options))
+		errs = append(errs, fieldErrs...)
+	}
+	if len(errs) > 0 {
+		return validationError(errs)
+	}
+
+	values := map[string]*KeyStorage{
+		LocationParams: &params,
+		LocationQuery:  &query,
+		LocationBody:   &body,
+	}
+	for _, f := range b.fields {
+		value := values[f.location].Get(f.key)
+		if value == nil {
+			continue
+		}
+		// Values are typecasted already, JSON is used to convert them to field types
+		data, err := json.Marshal(value)
+		if err != nil {
+			return NewServerError("bind", err.Error())
+		}
+		if err := json.Unmarshal(data, v.Elem().FieldByIndex(f.index).Addr().Interface()); err != nil {
+			return NewServerError("bind", fmt.Sprintf("%s: %v", f.key, err))
+		}
+	}
+	return nil
+}
+
+// requestBinding - cached binding of request type
+func requestBinding(t reflect.Type) *binding {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if b, ok := bindings.Load(t); ok {
+		return b.(*binding)
+	}
+	b := &binding{}
+	b.rules.Params = make(map[string]validation)
+	b.rules.Query = make(map[string]validation)
+	b.rules.Body = make(map[string]validation)
+	b.collect(t, nil)
+	if err := compileMethodRules(b.rules); err != nil {
+		panic(fmt.Sprintf("vodka: rules of %s: %v", t, err))
+	}
+	bindings.Store(t, b)
+	return b
+}
+
+// collect - collecting rules of struct fields. Fields of embedded structs are collected as own fields
+func (b *binding) collect(t reflect.Type, index []int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			b.collect(f.Type, fieldIndex)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		location, key := fieldLocation(f)
+		if key == "" {
+			continue
+		}
+		rule := fieldRules(f, map[reflect.Type]bool{t: true})
+		if rule.InputType == "" {
+			continue
+		}
+		switch location {
+		case LocationParams:
+			b.rules.Params[key] = rule
+		case LocationQuery:
+			b.rules.Query[key] = rule
+		default:
+			b.rules.Body[key] = rule
+		}
+		b.fields = append(b.fields, boundField{index: fieldIndex, location: location, key: key})
+	}
+}
+
+// fieldLocation - location and request name of field. Empty name for skipped fields
+func fieldLocation(f reflect.StructField) (string, string) {
+	if name := f.Tag.Get(TagParam); name != "" {
+		return LocationParams, name
+	}
+	if name := f.Tag.Get(TagQuery); name != "" {
+		return LocationQuery, name
+	}
+	return LocationBody, jsonName(f)
+}
+
+// jsonName - body name of field by json tag, field name if tag is not set
+func jsonName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+/*
+typeRules - validation rule of Go type: scalars by fieldType, structs as objects
+with properties, slices of scalars as []int64/[]float64/[]string, other slices as arrays.
+Seen are structs that are collected on the way to t: recursive struct (Node with []Node)
+is validated as object without properties below the first level
+*/
+func typeRules(t reflect.Type, seen map[reflect.Type]bool) validation {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.String() == "time.Time" {
+			return validation{InputType: "string", Format: FormatDateTime}
+		}
+		if seen[t] {
+			return validation{InputType: TypeObject}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		props := make(map[string]validation)
+		collectProperties(t, props, seen)
+		return validation{InputType: TypeObject, Properties: props}
+	case reflect.Map:
+		return validation{InputType: TypeObject}
+	case reflect.Slice, reflect.Array:
+		// []byte is sent as base64 string by encoding/json
+		if t.Elem().Kind() == reflect.Uint8 {
+			return validation{InputType: "string"}
+		}
+		switch fieldType(t.Elem()) {
+		case "int", "int64":
+			if min, _ := typeRange(t.Elem()); min == nil {
+				return validation{InputType: "[]int64"}
+			}
+		case "float64":
+			return validation{InputType: "[]float64"}
+		case "string":
+			if t.Elem().Kind() == reflect.String {
+				return validation{InputType: "[]string"}
+			}
+		}
+		items := typeRules(t.Elem(), seen)
+		return validation{InputType: TypeArray, Items: &items}
+	}
+	v := validation{InputType: fieldType(t)}
+	v.Min, v.Max = typeRange(t)
+	return v
+}
+
+func collectProperties(t reflect.Type, props map[string]validation, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			collectProperties(f.Type, props, seen)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		name := jsonName(f)
+		if name == "" {
+			continue
+		}
+		rule := fieldRules(f, seen)
+		if rule.InputType == "" {
+			continue
+		}
+		props[name] = rule
+	}
+}
+
+/*
+fieldRules - rule of field type with `validate` tag. Tag can narrow range of integer type but not widen it.
+Panics on malformed tag, so typo doesn't disable rule silently
+*/
+func fieldRules(f reflect.StructField, seen map[reflect.Type]bool) validation {
+	rule := typeRules(f.Type, seen)
+	min, max := rule.Min, rule.Max
+	if err := parseValidateTag(f.Tag.Get(TagValidate), &rule); err != nil {
+		panic(fmt.Sprintf("vodka: validate tag of field %s: %v", f.Name, err))
+	}
+	if min != nil && (rule.Min == nil || *rule.Min < *min) {
+		rule.Min = min
+	}
+	if max != nil && (rule.Max == nil || *rule.Max > *max) {
+		rule.Max = max
+	}
+	return rule
+}
+
+/*
+parseValidateTag - parsing `validate` tag: required,type=money,min=1,max=10,minLength=2,maxLength=100,
+enum=a|b,format=email,default=new,pattern=^[a-z]+$. Pattern takes the rest of tag, so it goes last
+*/
+func parseValidateTag(tag string, v *validation) error {
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, RulePattern+"=") {
+			part, tag = tag, ""
+		} else if i := strings.Index(tag, ","); i >= 0 {
+			part, tag = tag[:i], tag[i+1:]
+		} else {
+			part, tag = tag, ""
+		}
+		key, value := part, ""
+		if i := strings.Index(part, "="); i >= 0 {
+			key, value = part[:i], part[i+1:]
+		}
+		switch key {
+		case RuleRequired:
+			v.Required = true
+		case RuleMin:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("%s=%s is not a number", key, value)
+			}
+			v.Min = &n
+		case RuleMax:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("%s=%s is not a number", key, value)
+			}
+			v.Max = &n
+		case RuleMinLength:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s=%s is not a number", key, value)
+			}
+			v.MinLength = &n
+		case RuleMaxLength:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s=%s is not a number", key, value)
+			}
+			v.MaxLength = &n
+		case RulePattern:
+			v.Pattern = value
+		case RuleEnum:
+			for _, e := range strings.Split(value, "|") {
+				v.Enum = append(v.Enum, e)
+			}
+		case RuleFormat:
+			v.Format = value
+		case "default":
+			v.Default = value
+		case RuleType:
+			v.InputType = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package vodka
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testItem struct {
+	SKU    string `json:"sku" validate:"required"`
+	Amount uint   `json:"amount" validate:"min=1"`
+}
+
+type testCreateOrder struct {
+	UserID int64      `param:"id"`
+	Notify bool       `query:"notify" validate:"default=true"`
+	Status string     `json:"status" validate:"enum=new|paid,default=new"`
+	Code   string     `json:"code" validate:"pattern=^[A-Z]{2,3}$"`
+	Items  []testItem `json:"items" validate:"required,minLength=1"`
+	Tags   []string   `json:"tags"`
+	Secret string     `json:"-"`
+}
+
+type testNode struct {
+	Name     string     `json:"name"`
+	Children []testNode `json:"children"`
+}
+
+// newBindContext - context with raw request values
+func newBindContext(params, query map[string]interface{}, body string) *Context {
+	ctx := &Context{Raw: RawContext{Body: []byte(body)}}
+	for k, v := range params {
+		ctx.Raw.Params.Set(k, v)
+	}
+	for k, v := range query {
+		ctx.Raw.Query.Set(k, v)
+	}
+	return ctx
+}
+
+func TestBind(t *testing.T) {
+	ctx := newBindContext(
+		map[string]interface{}{"id": "42"},
+		nil,
+		`{"code":"AB","items":[{"sku":"a","amount":2}],"tags":["x","y"],"Secret":"s"}`,
+	)
+	var req testCreateOrder
+	if err := ctx.Bind(&req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := testCreateOrder{
+		UserID: 42,
+		Notify: true,
+		Status: "new",
+		Code:   "AB",
+		Items:  []testItem{{SKU: "a", Amount: 2}},
+		Tags:   []string{"x", "y"},
+	}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("got %+v, want %+v", req, want)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	ctx := newBindContext(
+		map[string]interface{}{"id": "x"},
+		map[string]interface{}{"notify": "maybe"},
+		`{"status":"closed","code":"A,B","items":[{"amount":-1}]}`,
+	)
+	err := ctx.Bind(&testCreateOrder{})
+	var e Error
+	if !errors.As(err, &e) || e.Status() != ErrorBadRequestCode {
+		t.Fatalf("expected 400 error, got %v", err)
+	}
+	errs, ok := e.Info.(ValidationErrors)
+	if !ok {
+		t.Fatalf("info is not ValidationErrors: %#v", e.Info)
+	}
+	var fields []string
+	for _, fe := range errs {
+		fields = append(fields, fe.Field+":"+fe.Rule)
+	}
+	want := []string{
+		"id:type", "notify:type",
+		"code:pattern", "items[0].amount:min", "items[0].sku:required", "status:enum",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("errors:\n got %v\nwant %v", fields, want)
+	}
+
+	if err := ctx.Bind(testCreateOrder{}); !errors.As(err, &e) || e.Status() != ErrorServerErrorCode {
+		t.Errorf("expected 500 error for destination that is not pointer, got %v", err)
+	}
+}
+
+func TestTypeRules(t *testing.T) {
+	zero, maxUint8 := 0.0, 255.0
+	minInt32, maxInt32 := -2147483648.0, 2147483647.0
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want validation
+	}{
+		{"uint", reflect.TypeOf(uint(0)), validation{InputType: "int", Min: &zero}},
+		{"uint8", reflect.TypeOf(uint8(0)), validation{InputType: "int", Min: &zero, Max: &maxUint8}},
+		{"int32", reflect.TypeOf(int32(0)), validation{InputType: "int", Min: &minInt32, Max: &maxInt32}},
+		{"int", reflect.TypeOf(0), validation{InputType: "int"}},
+		{"uint64", reflect.TypeOf(uint64(0)), validation{InputType: "int64", Min: &zero}},
+		{"bytes", reflect.TypeOf([]byte{}), validation{InputType: "string"}},
+		{"ints", reflect.TypeOf([]int{}), validation{InputType: "[]int64"}},
+		{"uints", reflect.TypeOf([]uint{}), validation{InputType: TypeArray, Items: &validation{InputType: "int", Min: &zero}}},
+		{"map", reflect.TypeOf(map[string]int{}), validation{InputType: TypeObject}},
+	}
+	for _, tt := range tests {
+		if got := typeRules(tt.typ, map[reflect.Type]bool{}); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+type testLimits struct {
+	Age   uint8 `json:"age" validate:"min=18,max=1000"`
+	Count int32 `json:"count"`
+}
+
+func TestBindRejectsValueOutOfRangeOfType(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"age":300}`, "age:max"},
+		{`{"age":10}`, "age:min"},
+		{`{"count":1099511627776}`, "count:max"},
+		{`{"count":-1099511627776}`, "count:min"},
+	}
+	for _, tt := range tests {
+		err := newBindContext(nil, nil, tt.body).Bind(&testLimits{})
+		var e Error
+		if !errors.As(err, &e) || e.Status() != ErrorBadRequestCode {
+			t.Errorf("%s: expected 400 error, got %v", tt.body, err)
+			continue
+		}
+		errs, _ := e.Info.(ValidationErrors)
+		if len(errs) != 1 || errs[0].Field+":"+errs[0].Rule != tt.want {
+			t.Errorf("%s: got %v, want %s", tt.body, errs, tt.want)
+		}
+	}
+
+	var dst testLimits
+	if err := newBindContext(nil, nil, `{"age":255,"count":-2147483648}`).Bind(&dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Age != 255 || dst.Count != -2147483648 {
+		t.Errorf("unexpected result: %+v", dst)
+	}
+}
+
+func TestTypeRulesOfRecursiveType(t *testing.T) {
+	rule := typeRules(reflect.TypeOf(testNode{}), map[reflect.Type]bool{})
+	children := rule.Properties["children"]
+	if children.InputType != TypeArray || children.Items == nil {
+		t.Fatalf("unexpected children rule: %+v", children)
+	}
+	if item := children.Items; item.InputType != TypeObject || item.Properties != nil {
+		t.Errorf("recursive type is expanded: %+v", item)
+	}
+}
+
+func TestParseValidateTagPattern(t *testing.T) {
+	var v validation
+	if err := parseValidateTag("required,min=1,pattern=^[a-z]{1,3}$", &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !v.Required || v.Min == nil || *v.Min != 1 || v.Pattern != "^[a-z]{1,3}$" {
+		t.Errorf("unexpected rule: %+v", v)
+	}
+}
+
+func TestMalformedValidateTagPanics(t *testing.T) {
+	for _, tag := range []string{"min=1O", "max=", "minLength=2.5", "maxLength=ten"} {
+		var v validation
+		if err := parseValidateTag(tag, &v); err == nil {
+			t.Errorf("%s: expected error", tag)
+		}
+	}
+
+	type malformed struct {
+		Age int `json:"age" validate:"min=1O"`
+	}
+	defer func() {
+		if p := recover(); p == nil || !strings.Contains(fmt.Sprint(p), "min=1O") {
+			t.Errorf("expected panic with malformed rule, got %v", p)
+		}
+	}()
+	newTestApp().Router.Request(malformed{}).POST("/users", func(ctx *Context) (interface{}, error) {
+		return nil, nil
+	})
+}
+
+func TestRequestTypeIsInheritedByGroups(t *testing.T) {
+	app := newTestApp()
+	orders := app.Router.Request(testCreateOrder{}).Group("/users/:id")
+	orders.POST("/orders", func(ctx *Context) (interface{}, error) {
+		var req testCreateOrder
+		if err := ctx.Bind(&req); err != nil {
+			return nil, err
+		}
+		return req.UserID, nil
+	})
+
+	w := serve(app, httptest.NewRequest("POST", "/users/7/orders", strings.NewReader(`{"items":[]}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 by rules of request type, got %d: %s", w.Code, w.Body.String())
+	}
+	w = serve(app, httptest.NewRequest("POST", "/users/7/orders", strings.NewReader(`{"items":[{"sku":"a"}]}`)))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"data":7`) {
+		t.Errorf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -1,11 +1,53 @@
 package builders
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+// identifier - allowed column name (with optional table alias) for parts that can't be bound
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+var (
+	// selectField - column with optional alias: "name as status_name"
+	selectField = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?(\s+(?i:as)\s+[A-Za-z_][A-Za-z0-9_]*)?$`)
+	// whereKey - column with optional operator in key: "amount>="
+	whereKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?\s*(=|<>|!=|>=|<=|>|<)?$`)
+	// joinType - LEFT, RIGHT, INNER, FULL or CROSS with optional OUTER
+	joinType = regexp.MustCompile(`^(?i:(LEFT|RIGHT|INNER|FULL|CROSS)?(\s+OUTER)?)$`)
+)
+
+// IsIdentifier - checking that name can be written into SQL: column or table with optional alias
+func IsIdentifier(name string) bool {
+	return identifier.MatchString(name)
+}
+
+// ErrInvalidIdentifier - table, column or constraint name can't be written into SQL
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+/*
+IdentifierError - name that is not valid identifier and part of query where it is used.
+Unwraps to ErrInvalidIdentifier
+*/
+type IdentifierError struct {
+	Part string
+	Name string
+}
+
+func (e *IdentifierError) Error() string {
+	return fmt.Sprintf("%s %q is not valid identifier", e.Part, e.Name)
+}
+
+// Unwrap - ErrInvalidIdentifier
+func (e *IdentifierError) Unwrap() error {
+	return ErrInvalidIdentifier
+}
+
 // NewPostgres - Postgres SQL builder
 func NewPostgres() Builder {
 	return &postgres{}
@@ -21,6 +63,7 @@ Builder - interface for query builder for adapter and data service
 */
 type Builder interface {
 	Select([]string) Builder
+	Count() Builder
 	Insert(string) Builder
 	Save(string) Builder
 	Update(string) Builder
@@ -30,13 +73,17 @@ type Builder interface {
 	Set(interface{}) Builder
 	From(string) Builder
 	Where(map[string]interface{}) Builder
+	WhereCond(...Condition) Builder
 	Limit(int, int) Builder
 	Join(Join) Builder
 	Order(OrderParam) Builder
 	OnConflictAction(string) Builder
 	OnConflictFields([]string) Builder
 	OnConflictConstraint(string) Builder
+	// Build - SQL with values written into text. Empty if identifiers are not valid
 	Build() string
+	// BuildWithArgs - SQL with placeholders and values for them. IdentifierError if names are not valid
+	BuildWithArgs() (string, []interface{}, error)
 }
 
 /*
@@ -61,6 +108,7 @@ type parts struct {
 	table                string
 	fields               []string
 	where                map[string]interface{}
+	conditions           []Condition
 	join                 []Join
 	order                []OrderParam
 	limit                int
@@ -72,6 +120,75 @@ type parts struct {
 	onConflictConstraint string
 }
 
+/*
+validate - checking names that are written into SQL text as is: table, fields, columns
+of conditions, values and order, joins and conflict target. Values are bound, names can't be
+*/
+func (p *parts) validate() error {
+	if p.table != "" && !identifier.MatchString(p.table) {
+		return &IdentifierError{Part: "table", Name: p.table}
+	}
+	for _, f := range p.fields {
+		if f != "*" && !selectField.MatchString(f) {
+			return &IdentifierError{Part: "field", Name: f}
+		}
+	}
+	for _, key := range sortedKeys(p.where) {
+		if column, _ := SplitFilter(key); !whereKey.MatchString(column) {
+			return &IdentifierError{Part: "column", Name: column}
+		}
+	}
+	for _, c := range p.conditions {
+		if c == nil {
+			continue
+		}
+		for _, column := range c.columns() {
+			if !whereKey.MatchString(column) {
+				return &IdentifierError{Part: "column", Name: column}
+			}
+		}
+	}
+	for _, j := range p.join {
+		for _, name := range []string{j.Source, j.Key, j.TargetKey} {
+			if !identifier.MatchString(name) {
+				return &IdentifierError{Part: "join", Name: name}
+			}
+		}
+		for _, f := range j.Fields {
+			if !selectField.MatchString(f) {
+				return &IdentifierError{Part: "field", Name: f}
+			}
+		}
+		if !joinType.MatchString(j.Type) {
+			return &IdentifierError{Part: "join type", Name: j.Type}
+		}
+	}
+	for _, o := range p.order {
+		if !identifier.MatchString(o.OrderBy) {
+			return &IdentifierError{Part: "order", Name: o.OrderBy}
+		}
+	}
+	if data, ok := p.insertData.(map[string]interface{}); ok {
+		for _, key := range sortedKeys(data) {
+			if !identifier.MatchString(key) {
+				return &IdentifierError{Part: "column", Name: key}
+			}
+		}
+	}
+	if p.returnID != "" && !identifier.MatchString(p.returnID) {
+		return &IdentifierError{Part: "returning", Name: p.returnID}
+	}
+	for _, f := range p.onConflictFields {
+		if !identifier.MatchString(f) {
+			return &IdentifierError{Part: "conflict field", Name: f}
+		}
+	}
+	if p.onConflictConstraint != "" && !identifier.MatchString(p.onConflictConstraint) {
+		return &IdentifierError{Part: "constraint", Name: p.onConflictConstraint}
+	}
+	return nil
+}
+
 func formatValue(value interface{}) (fv string) {
 	if v, ok := value.(string); ok {
 		fv = "= '" + v + "'"
@@ -117,3 +234,116 @@ func toString(value interface{}) (str string) {
 	}
 	return
 }
+
+/*
+toList - converting slice values to []interface{} for IN statement.
+Any slice except []byte is a list: []int, []int64, []string, []interface{}...
+*/
+func toList(value interface{}) (list []interface{}, ok bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []byte:
+		return nil, false
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	for i := 0; i < rv.Len(); i++ {
+		list = append(list, rv.Index(i).Interface())
+	}
+	return list, true
+}
+
+// dialect - SQL dialect specific parts of builder
+type dialect interface {
+	bind(interface{}) string
+	bindList([]interface{}) string
+	ilike() string
+}
+
+/*
+SplitFilter - splitting Where key into column and filter operator: amount__gte -> amount, gte.
+Operator is empty if key has no known operator
+*/
+func SplitFilter(key string) (column, operator string) {
+	i := strings.LastIndex(key, FilterSeparator)
+	if i <= 0 {
+		return key, ""
+	}
+	operator = key[i+len(FilterSeparator):]
+	if !IsFilterOperator(operator) {
+		return key, ""
+	}
+	return key[:i], operator
+}
+
+// IsFilterOperator - checking that operator is supported by builders
+func IsFilterOperator(operator string) bool {
+	if _, ok := comparisons[operator]; ok {
+		return true
+	}
+	switch operator {
+	case FilterLike, FilterILike, FilterIn, FilterNotIn, FilterIsNull, FilterBetween:
+		return true
+	}
+	return false
+}
+
+// buildCondition - building single WHERE condition for column with filter operator
+func buildCondition(d dialect, column, operator string, value interface{}) string {
+	list, isList := toList(value)
+	switch operator {
+	case "":
+		if isList {
+			return column + " IN " + d.bindList(list)
+		}
+		// operator is set in key itself: "amount>"
+		if strings.ContainsAny(column, "=<>") {
+			return column + d.bind(value)
+		}
+		return column + "=" + d.bind(value)
+	case FilterIn, FilterNotIn:
+		if !isList {
+			list = []interface{}{value}
+		}
+		if len(list) == 0 {
+			// nothing is IN empty list and everything is NOT IN it
+			if operator == FilterIn {
+				return "1=0"
+			}
+			return "1=1"
+		}
+		if operator == FilterNotIn {
+			return column + " NOT IN " + d.bindList(list)
+		}
+		return column + " IN " + d.bindList(list)
+	case FilterIsNull:
+		if isNull, ok := value.(bool); ok && !isNull {
+			return column + " IS NOT NULL"
+		}
+		return column + " IS NULL"
+	case FilterBetween:
+		if !isList || len(list) != 2 {
+			// BETWEEN requires exactly two values, otherwise nothing matches
+			return "1=0"
+		}
+		return column + " BETWEEN " + d.bind(list[0]) + " AND " + d.bind(list[1])
+	case FilterLike:
+		return column + " LIKE " + d.bind(value)
+	case FilterILike:
+		return column + " " + d.ilike() + " " + d.bind(value)
+	}
+	return column + " " + comparisons[operator] + " " + d.bind(value)
+}
+
+// sortedKeys - map keys in stable order
+func sortedKeys(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,235 @@
+package builders
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuildWithArgsPlaceholders(t *testing.T) {
+	where := map[string]interface{}{"status": "a", "age__gte": 18, "id": []int64{1, 2}}
+	tests := []struct {
+		name    string
+		builder Builder
+		sql     string
+		args    []interface{}
+	}{
+		{
+			name:    "postgres select",
+			builder: NewPostgres().Select([]string{"id", "name"}).From("users").Where(where).Limit(10, 20),
+			sql:     "SELECT t.id, t.name FROM  users as t WHERE t.age >= $1 AND t.id IN ($2,$3) AND t.status=$4 LIMIT 10 OFFSET 20",
+			args:    []interface{}{18, int64(1), int64(2), "a"},
+		},
+		{
+			name:    "mysql select",
+			builder: NewMySQL().Select([]string{"id", "name"}).From("users").Where(where).Limit(10, 20),
+			sql:     "SELECT t.id, t.name FROM  users as t WHERE t.age >= ? AND t.id IN (?,?) AND t.status=? LIMIT 10 OFFSET 20",
+			args:    []interface{}{18, int64(1), int64(2), "a"},
+		},
+		{
+			name:    "postgres insert",
+			builder: NewPostgres().Insert("users").Values(map[string]interface{}{"name": "x", "age": 3}),
+			sql:     "INSERT INTO users(age,name) VALUES ($1,$2)",
+			args:    []interface{}{3, "x"},
+		},
+		{
+			name:    "mysql insert",
+			builder: NewMySQL().Insert("users").Values(map[string]interface{}{"name": "x", "age": 3}),
+			sql:     "INSERT INTO users(`age`,`name`) VALUES (?,?)",
+			args:    []interface{}{3, "x"},
+		},
+		{
+			name:    "postgres update numbers where after set",
+			builder: NewPostgres().Update("users").Set(map[string]interface{}{"name": "x", "age": 3}).Where(map[string]interface{}{"id": 5}),
+			sql:     "UPDATE users as t SET age = $1, name = $2 WHERE t.id=$3",
+			args:    []interface{}{3, "x", 5},
+		},
+		{
+			name: "postgres save binds values twice",
+			builder: NewPostgres().Save("users").Values(map[string]interface{}{"name": "x"}).
+				OnConflictConstraint("users_name_key").OnConflictAction("update"),
+			sql:  "INSERT INTO users(name) VALUES ($1) ON CONFLICT ON CONSTRAINT users_name_key DO UPDATE SET name = $2 RETURNING *",
+			args: []interface{}{"x", "x"},
+		},
+		{
+			name:    "postgres delete",
+			builder: NewPostgres().Delete().From("users").Where(map[string]interface{}{"id": 5}),
+			sql:     "DELETE FROM  users as t WHERE t.id=$1",
+			args:    []interface{}{5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.BuildWithArgs()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.sql {
+				t.Errorf("SQL:\n got %s\nwant %s", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args: got %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestBuildWithArgsIsRepeatable(t *testing.T) {
+	b := NewPostgres().Select(nil).From("users").Where(map[string]interface{}{"id": 1})
+	first, _, _ := b.BuildWithArgs()
+	second, args, _ := b.BuildWithArgs()
+	if first != second || len(args) != 1 {
+		t.Errorf("second build differs: %s, %s, %v", first, second, args)
+	}
+}
+
+func TestBuildWithArgsRejectsIdentifiers(t *testing.T) {
+	const bad = "id) OR 1=1 --"
+	values := map[string]interface{}{"name": "x"}
+	tests := []struct {
+		name    string
+		builder func(Builder) Builder
+		part    string
+	}{
+		{"table", func(b Builder) Builder { return b.Select(nil).From(bad) }, "table"},
+		{"field", func(b Builder) Builder { return b.Select([]string{bad}).From("users") }, "field"},
+		{"where key", func(b Builder) Builder {
+			return b.Select(nil).From("users").Where(map[string]interface{}{bad: 1})
+		}, "column"},
+		{"where key with filter", func(b Builder) Builder {
+			return b.Select(nil).From("users").Where(map[string]interface{}{bad + "__gte": 1})
+		}, "column"},
+		{"condition", func(b Builder) Builder {
+			return b.Select(nil).From("users").WhereCond(Or(Cond("id", "=", 1), Not(Cond(bad, "=", 1))))
+		}, "column"},
+		{"keyset", func(b Builder) Builder {
+			return b.Select(nil).From("users").WhereCond(After([]string{bad}, []interface{}{1}, false))
+		}, "column"},
+		{"order", func(b Builder) Builder {
+			return b.Select(nil).From("users").Order(OrderParam{OrderBy: bad})
+		}, "order"},
+		{"join", func(b Builder) Builder {
+			return b.Select(nil).From("users").Join(Join{Source: "roles", Key: "id", TargetKey: bad})
+		}, "join"},
+		{"join type", func(b Builder) Builder {
+			return b.Select(nil).From("users").Join(Join{Source: "roles", Key: "id", TargetKey: "role_id", Type: "LEFT JOIN x;"})
+		}, "join type"},
+		{"insert column", func(b Builder) Builder {
+			return b.Insert("users").Values(map[string]interface{}{"name`) VALUES (1); DROP TABLE u; --": 1})
+		}, "column"},
+		{"update column", func(b Builder) Builder {
+			return b.Update("users").Set(map[string]interface{}{bad: 1}).Where(map[string]interface{}{"id": 1})
+		}, "column"},
+		{"conflict constraint", func(b Builder) Builder {
+			return b.Save("users").Values(values).OnConflictConstraint(bad).OnConflictAction("nothing")
+		}, "constraint"},
+		{"conflict field", func(b Builder) Builder {
+			return b.Save("users").Values(values).OnConflictFields([]string{bad}).OnConflictAction("nothing")
+		}, "conflict field"},
+		{"returning", func(b Builder) Builder { return b.Insert("users").Values(values).ReturnID(bad) }, "returning"},
+	}
+	for _, tt := range tests {
+		for dialect, b := range map[string]Builder{"postgres": NewPostgres(), "mysql": NewMySQL()} {
+			t.Run(dialect+" "+tt.name, func(t *testing.T) {
+				builder := tt.builder(b)
+				sql, args, err := builder.BuildWithArgs()
+				if !errors.Is(err, ErrInvalidIdentifier) {
+					t.Fatalf("expected ErrInvalidIdentifier, got %v (%s)", err, sql)
+				}
+				var idErr *IdentifierError
+				if !errors.As(err, &idErr) || idErr.Part != tt.part {
+					t.Errorf("expected part %q, got %v", tt.part, err)
+				}
+				if sql != "" || args != nil {
+					t.Errorf("SQL is built for invalid identifier: %s %v", sql, args)
+				}
+				if inline := builder.Build(); inline != "" {
+					t.Errorf("Build() returned SQL for invalid identifier: %s", inline)
+				}
+			})
+		}
+	}
+}
+
+func TestBuildWithArgsAllowsIdentifiers(t *testing.T) {
+	b := NewPostgres().Select([]string{"id", "name as user_name"}).From("public.users").
+		Where(map[string]interface{}{"t.status": "a", "amount>=": 10}).
+		Join(Join{Source: "public.roles", Key: "id", TargetKey: "role_id", Type: "left outer", Fields: []string{"name as role"}}).
+		Order(OrderParam{OrderBy: "created_at", Desc: true})
+	if _, _, err := b.BuildWithArgs(); err != nil {
+		t.Errorf("valid identifiers are rejected: %v", err)
+	}
+}
+
+func TestSplitFilter(t *testing.T) {
+	tests := []struct{ key, column, operator string }{
+		{"amount__gte", "amount", FilterGte},
+		{"created_at__between", "created_at", FilterBetween},
+		{"user__name", "user__name", ""},
+		{"amount", "amount", ""},
+		{"__in", "__in", ""},
+	}
+	for _, tt := range tests {
+		column, operator := SplitFilter(tt.key)
+		if column != tt.column || operator != tt.operator {
+			t.Errorf("SplitFilter(%q) = %q, %q, want %q, %q", tt.key, column, operator, tt.column, tt.operator)
+		}
+	}
+}
+
+func TestWhereFilterOperators(t *testing.T) {
+	tests := []struct {
+		where    map[string]interface{}
+		postgres string
+		mysql    string
+		args     []interface{}
+	}{
+		{map[string]interface{}{"amount__eq": 3}, "t.amount = $1", "t.amount = ?", []interface{}{3}},
+		{map[string]interface{}{"amount__ne": 3}, "t.amount <> $1", "t.amount <> ?", []interface{}{3}},
+		{map[string]interface{}{"amount__gte": 10}, "t.amount >= $1", "t.amount >= ?", []interface{}{10}},
+		{map[string]interface{}{"amount__lt": 10}, "t.amount < $1", "t.amount < ?", []interface{}{10}},
+		{map[string]interface{}{"name__like": "ab%"}, "t.name LIKE $1", "t.name LIKE ?", []interface{}{"ab%"}},
+		{map[string]interface{}{"name__ilike": "ab%"}, "t.name ILIKE $1", "t.name LIKE ?", []interface{}{"ab%"}},
+		{map[string]interface{}{"status__in": []interface{}{"a", "b"}}, "t.status IN ($1,$2)", "t.status IN (?,?)", []interface{}{"a", "b"}},
+		{map[string]interface{}{"status__nin": []string{"a", "b"}}, "t.status NOT IN ($1,$2)", "t.status NOT IN (?,?)", []interface{}{"a", "b"}},
+		{map[string]interface{}{"deleted_at__isnull": true}, "t.deleted_at IS NULL", "t.deleted_at IS NULL", nil},
+		{map[string]interface{}{"deleted_at__isnull": false}, "t.deleted_at IS NOT NULL", "t.deleted_at IS NOT NULL", nil},
+		{map[string]interface{}{"amount__between": []interface{}{1, 5}}, "t.amount BETWEEN $1 AND $2", "t.amount BETWEEN ? AND ?", []interface{}{1, 5}},
+	}
+	for _, tt := range tests {
+		for dialect, b := range map[string]Builder{tt.postgres: NewPostgres(), tt.mysql: NewMySQL()} {
+			sql, args, err := b.Select([]string{"id"}).From("t").Where(tt.where).BuildWithArgs()
+			if err != nil {
+				t.Fatalf("%v: unexpected error: %v", tt.where, err)
+			}
+			if want := "SELECT t.id FROM  t as t WHERE " + dialect; sql != want {
+				t.Errorf("%v:\n got %s\nwant %s", tt.where, sql, want)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("%v: args %v, want %v", tt.where, args, tt.args)
+			}
+		}
+	}
+}
+
+func TestToList(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		list  []interface{}
+		ok    bool
+	}{
+		{"interfaces", []interface{}{1, "a"}, []interface{}{1, "a"}, true},
+		{"ints", []int{1, 2}, []interface{}{1, 2}, true},
+		{"strings", []string{"a", "b"}, []interface{}{"a", "b"}, true},
+		{"array", [2]int64{1, 2}, []interface{}{int64(1), int64(2)}, true},
+		{"bytes are scalar", []byte("ab"), nil, false},
+		{"scalar", 1, nil, false},
+	}
+	for _, tt := range tests {
+		list, ok := toList(tt.value)
+		if ok != tt.ok || !reflect.DeepEqual(list, tt.list) {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.name, list, ok, tt.list, tt.ok)
+		}
+	}
+}
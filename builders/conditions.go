@@ -0,0 +1,201 @@
+package builders
+
+import (
+	"strings"
+)
+
+/*
+Condition - part of WHERE statement. Conditions are created with Cond and Map
+and composed with And, Or and Not
+*/
+type Condition interface {
+	render(d dialect, column func(string) string) string
+	// columns - names that are written into SQL, checked by builder
+	columns() []string
+}
+
+type cond struct {
+	column   string
+	operator string
+	value    interface{}
+}
+
+type group struct {
+	operator string
+	items    []Condition
+}
+
+type not struct {
+	item Condition
+}
+
+type keyset struct {
+	names  []string
+	values []interface{}
+	desc   bool
+}
+
+// operators - SQL operators that can be used in Cond
+var operators = map[string]string{
+	"=":           FilterEq,
+	"<>":          FilterNe,
+	"!=":          FilterNe,
+	">":           FilterGt,
+	">=":          FilterGte,
+	"<":           FilterLt,
+	"<=":          FilterLte,
+	"like":        FilterLike,
+	"ilike":       FilterILike,
+	"in":          FilterIn,
+	"not in":      FilterNotIn,
+	"is null":     FilterIsNull,
+	"is not null": FilterIsNull,
+	"between":     FilterBetween,
+}
+
+/*
+Cond - single condition: Cond("amount", ">", 10), Cond("status", "IN", []string{"a", "b"}).
+Operator may be SQL operator or filter operator (gte, nin, isnull...).
+Panics on unknown operator
+*/
+func Cond(column, operator string, value interface{}) Condition {
+	op := strings.ToLower(strings.TrimSpace(operator))
+	if op == "is not null" {
+		value = false
+	}
+	if f, ok := operators[op]; ok {
+		op = f
+	}
+	if !IsFilterOperator(op) {
+		panic("builders: unknown operator " + operator)
+	}
+	return cond{column: column, operator: op, value: value}
+}
+
+/*
+Map - conditions from map key=value joined by AND. Keys may contain filter operator: amount__gte.
+Keys are sorted so SQL is the same for the same map
+*/
+func Map(where map[string]interface{}) Condition {
+	g := group{operator: "AND"}
+	for _, key := range sortedKeys(where) {
+		column, operator := SplitFilter(key)
+		g.items = append(g.items, cond{column: column, operator: operator, value: where[key]})
+	}
+	return g
+}
+
+// And - all conditions must match
+func And(items ...Condition) Condition {
+	return group{operator: "AND", items: items}
+}
+
+// Or - one of conditions must match
+func Or(items ...Condition) Condition {
+	return group{operator: "OR", items: items}
+}
+
+// Not - negation of condition
+func Not(item Condition) Condition {
+	return not{item: item}
+}
+
+/*
+After - keyset condition for rows after cursor: (created_at, id) > ($1, $2).
+For descending order rows are compared with <
+*/
+func After(columns []string, values []interface{}, desc bool) Condition {
+	return keyset{names: columns, values: values, desc: desc}
+}
+
+func (c cond) render(d dialect, column func(string) string) string {
+	return buildCondition(d, column(c.column), c.operator, c.value)
+}
+
+func (c cond) columns() []string {
+	return []string{c.column}
+}
+
+func (g group) render(d dialect, column func(string) string) string {
+	var parts []string
+	for _, item := range g.items {
+		if item == nil {
+			continue
+		}
+		str := item.render(d, column)
+		if str == "" {
+			continue
+		}
+		if sub, ok := item.(group); ok && sub.operator != g.operator && sub.size() > 1 {
+			str = "(" + str + ")"
+		}
+		parts = append(parts, str)
+	}
+	return strings.Join(parts, " "+g.operator+" ")
+}
+
+// size - count of conditions in group that are not empty
+func (g group) size() (n int) {
+	for _, item := range g.items {
+		if sub, ok := item.(group); ok {
+			if sub.size() > 0 {
+				n++
+			}
+			continue
+		}
+		if item != nil {
+			n++
+		}
+	}
+	return
+}
+
+func (g group) columns() (columns []string) {
+	for _, item := range g.items {
+		if item != nil {
+			columns = append(columns, item.columns()...)
+		}
+	}
+	return
+}
+
+func (n not) render(d dialect, column func(string) string) string {
+	if n.item == nil {
+		return ""
+	}
+	str := n.item.render(d, column)
+	if str == "" {
+		return ""
+	}
+	return "NOT (" + str + ")"
+}
+
+func (n not) columns() []string {
+	if n.item == nil {
+		return nil
+	}
+	return n.item.columns()
+}
+
+func (k keyset) render(d dialect, column func(string) string) string {
+	if len(k.names) == 0 || len(k.names) != len(k.values) {
+		return ""
+	}
+	var columns []string
+	for _, c := range k.names {
+		if !identifier.MatchString(c) {
+			// builder returns IdentifierError, nothing matches if SQL is built anyway
+			return "1=0"
+		}
+		columns = append(columns, column(c))
+	}
+	sign := " > "
+	if k.desc {
+		sign = " < "
+	}
+	return "(" + strings.Join(columns, ", ") + ")" + sign + d.bindList(k.values)
+}
+
+func (k keyset) columns() []string {
+	return k.names
+}
@@ -0,0 +1,85 @@
+package builders
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Condition of WhereCond is joined with other WHERE parts by AND, so OR group is wrapped in parentheses
+func TestConditionGroups(t *testing.T) {
+	tests := []struct {
+		name     string
+		cond     Condition
+		postgres string
+		mysql    string
+		args     []interface{}
+	}{
+		{
+			name:     "or of and with not",
+			cond:     Or(Cond("a", "=", 1), And(Cond("b", ">", 2), Not(Cond("c", "IN", []int{3, 4})))),
+			postgres: "(t.a = $1 OR (t.b > $2 AND NOT (t.c IN ($3,$4))))",
+			mysql:    "(t.a = ? OR (t.b > ? AND NOT (t.c IN (?,?))))",
+			args:     []interface{}{1, 2, 3, 4},
+		},
+		{
+			name:     "same operator is not wrapped",
+			cond:     And(Cond("a", "=", 1), And(Cond("b", "=", 2), Cond("c", "=", 3))),
+			postgres: "t.a = $1 AND t.b = $2 AND t.c = $3",
+			mysql:    "t.a = ? AND t.b = ? AND t.c = ?",
+			args:     []interface{}{1, 2, 3},
+		},
+		{
+			name:     "empty and nil items are skipped",
+			cond:     Or(nil, And(), Not(nil), Cond("a", "is not null", nil)),
+			postgres: "(t.a IS NOT NULL)",
+			mysql:    "(t.a IS NOT NULL)",
+		},
+		{
+			name:     "map keys are sorted",
+			cond:     Or(Map(map[string]interface{}{"z": 1, "a__gte": 2, "m": 3}), Cond("x", "between", []int{1, 2})),
+			postgres: "((t.a >= $1 AND t.m=$2 AND t.z=$3) OR t.x BETWEEN $4 AND $5)",
+			mysql:    "((t.a >= ? AND t.m=? AND t.z=?) OR t.x BETWEEN ? AND ?)",
+			args:     []interface{}{2, 3, 1, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for want, b := range map[string]Builder{tt.postgres: NewPostgres(), tt.mysql: NewMySQL()} {
+				sql, args, err := b.Select([]string{"id"}).From("t").WhereCond(tt.cond).BuildWithArgs()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if want = "SELECT t.id FROM  t as t WHERE " + want; sql != want {
+					t.Errorf("SQL:\n got %s\nwant %s", sql, want)
+				}
+				if !reflect.DeepEqual(args, tt.args) {
+					t.Errorf("args: got %v, want %v", args, tt.args)
+				}
+			}
+		})
+	}
+}
+
+func TestWhereCondIsJoinedWithWhere(t *testing.T) {
+	sql, args, err := NewPostgres().Select([]string{"id"}).From("t").
+		Where(map[string]interface{}{"x": 1}).
+		WhereCond(Or(Cond("a", "=", 2), Cond("b", "=", 3))).BuildWithArgs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "SELECT t.id FROM  t as t WHERE t.x=$1 AND (t.a = $2 OR t.b = $3)"; sql != want {
+		t.Errorf("SQL:\n got %s\nwant %s", sql, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, 2, 3}) {
+		t.Errorf("args: got %v", args)
+	}
+}
+
+func TestCondPanicsOnUnknownOperator(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	Cond("a", "~~", 1)
+}
@@ -13,6 +13,8 @@ type mysql struct {
 	queryType string
 	parts     parts
 	sources   map[string]string // map that contains tables with aliases
+	args      []interface{}     // bind arguments collected while building
+	inline    bool              // values are written into SQL text instead of placeholders
 }
 
 /*
@@ -24,6 +26,14 @@ func (sql *mysql) Select(fields []string) Builder {
 	return sql
 }
 
+/*
+Count - will set query type to SELECT COUNT(*). Order and limit are ignored
+*/
+func (sql *mysql) Count() Builder {
+	sql.queryType = queryTypeCount
+	return sql
+}
+
 /*
 Insert - will set query type to INSERT and sets table
 */
@@ -100,6 +110,15 @@ func (sql *mysql) Where(where map[string]interface{}) Builder {
 	return sql
 }
 
+/*
+WhereCond - conditions composed with And/Or/Not for SELECT/UPDATE/DELETE.
+Joined by AND with conditions from Where
+*/
+func (sql *mysql) WhereCond(conditions ...Condition) Builder {
+	sql.parts.conditions = append(sql.parts.conditions, conditions...)
+	return sql
+}
+
 /*
 Join - join source with params into query.
 Every table in SQL query have to have Alias. If you'll not provide - it will be generated
@@ -146,12 +165,38 @@ func (sql *mysql) OnConflictConstraint(constraint string) Builder {
 }
 
 /*
-Build - method that builds from params into SQL string
+Build - method that builds from params into SQL string.
+Values are written into SQL text, so use BuildWithArgs for user provided data.
+Returns empty string if names of tables or columns are not valid
 */
 func (sql mysql) Build() string {
+	if sql.parts.validate() != nil {
+		return ""
+	}
+	sql.inline = true
+	return sql.build()
+}
+
+/*
+BuildWithArgs - builds SQL string with placeholders and returns values as arguments for it.
+Names of tables and columns are checked, IdentifierError is returned for names that are not valid
+*/
+func (sql mysql) BuildWithArgs() (string, []interface{}, error) {
+	if err := sql.parts.validate(); err != nil {
+		return "", nil, err
+	}
+	sql.args = nil
+	SQL := sql.build()
+	return SQL, sql.args, nil
+}
+
+func (sql *mysql) build() string {
 	if sql.queryType == queryTypeSelect {
 		return sql.buildSelect()
 	}
+	if sql.queryType == queryTypeCount {
+		return sql.buildCount()
+	}
 	if sql.queryType == queryTypeInsert {
 		return sql.buildInsert()
 	}
@@ -192,9 +237,10 @@ func (sql *mysql) buildValues() string {
 	var values []string
 
 	if data, ok := sql.parts.insertData.(map[string]interface{}); ok {
-		for key, value := range data {
+		for _, key := range sortedKeys(data) {
+			value := data[key]
 			keys = append(keys, "`"+key+"`")
-			values = append(values, toString(value))
+			values = append(values, sql.bind(value))
 		}
 	}
 	return "(" + strings.Join(keys, ",") + ") VALUES (" + strings.Join(values, ",") + ")"
@@ -211,6 +257,14 @@ func (sql *mysql) buildSelect() (SQL string) {
 	return
 }
 
+func (sql *mysql) buildCount() (SQL string) {
+	SQL = queryTypeSelect + " COUNT(*)"
+	SQL += sql.buildFrom(true)
+	SQL += sql.buildJoin()
+	SQL += sql.buildWhere(true)
+	return
+}
+
 func (sql *mysql) buildFrom(alias bool) string {
 	return " FROM " + sql.buildTable(alias)
 }
@@ -249,62 +303,30 @@ func (sql *mysql) buildJoin() (join string) {
 }
 
 func (sql *mysql) buildWhere(alias bool) (where string) {
-	if len(sql.parts.where) == 0 {
-		return
-	}
-	where = " WHERE "
-	var w []string
-	for key, value := range sql.parts.where {
-		if sl, ok := value.([]int64); ok {
-			var str []string
-			for _, st := range sl {
-				str = append(str, strconv.FormatInt(st, 10))
-			}
-			if alias {
-				w = append(w, sql.getAliasBySource(sql.parts.table)+"."+key+" IN ("+strings.Join(str, ",")+")")
-				continue
-			} else {
-				w = append(w, key+" IN ("+strings.Join(str, ",")+")")
-				continue
-			}
-		}
-		if sl, ok := value.([]string); ok {
-			var str []string
-			for _, st := range sl {
-				str = append(str, `'`+st+`'`)
-			}
-			if alias {
-				w = append(w, sql.getAliasBySource(sql.parts.table)+"."+key+" IN ("+strings.Join(str, ",")+")")
-				continue
-			} else {
-				w = append(w, key+" IN ("+strings.Join(str, ",")+")")
-				continue
-			}
-		}
-		str := toString(value)
-		sign := ""
-		if strings.Index(key, "=") == -1 && strings.Index(key, ">") == -1 && strings.Index(key, "<") == -1 {
-			sign = "="
-		}
-		if alias {
-			w = append(w, sql.getAliasBySource(sql.parts.table)+"."+key+sign+str)
-		} else {
-			w = append(w, key+sign+str)
+	column := func(name string) string {
+		if !alias || strings.Contains(name, ".") {
+			return name
 		}
+		return sql.getAliasBySource(sql.parts.table) + "." + name
+	}
+	conditions := append([]Condition{Map(sql.parts.where)}, sql.parts.conditions...)
+	where = And(conditions...).render(sql, column)
+	if where == "" {
+		return
 	}
-	return where + strings.Join(w, " AND ")
+	return " WHERE " + where
 }
 
 func (sql *mysql) buildSetter() (where string) {
-	if len(sql.parts.where) == 0 {
+	if len(sql.parts.where) == 0 && len(sql.parts.conditions) == 0 {
 		return
 	}
 	where = " SET "
 	var w []string
 	if data, ok := sql.parts.insertData.(map[string]interface{}); ok {
-		for key, value := range data {
-			str := toString(value)
-			w = append(w, "`"+key+"` = "+str)
+		for _, key := range sortedKeys(data) {
+			value := data[key]
+			w = append(w, "`"+key+"` = "+sql.bind(value))
 		}
 	}
 	return where + strings.Join(w, ", ")
@@ -343,6 +365,29 @@ func (sql *mysql) buildOrderBy() (order string) {
 	return
 }
 
+// bind - returns placeholder for value and saves value as query argument
+func (sql *mysql) bind(value interface{}) string {
+	if sql.inline {
+		return toString(value)
+	}
+	sql.args = append(sql.args, value)
+	return "?"
+}
+
+// bindList - returns list of placeholders for IN statement
+func (sql *mysql) bindList(values []interface{}) string {
+	var list []string
+	for _, v := range values {
+		list = append(list, sql.bind(v))
+	}
+	return "(" + strings.Join(list, ",") + ")"
+}
+
+// ilike - MySQL LIKE is case insensitive for default collations
+func (sql *mysql) ilike() string {
+	return "LIKE"
+}
+
 func (sql *mysql) addToSources(table, id string) {
 	if sql.sources == nil {
 		sql.sources = make(map[string]string)
@@ -13,6 +13,8 @@ type postgres struct {
 	queryType string
 	parts     parts
 	sources   map[string]string // map that contains tables with aliases
+	args      []interface{}     // bind arguments collected while building
+	inline    bool              // values are written into SQL text instead of placeholders
 }
 
 /*
@@ -24,6 +26,14 @@ func (sql *postgres) Select(fields []string) Builder {
 	return sql
 }
 
+/*
+Count - will set query type to SELECT COUNT(*). Order and limit are ignored
+*/
+func (sql *postgres) Count() Builder {
+	sql.queryType = queryTypeCount
+	return sql
+}
+
 /*
 Insert - will set query type to INSERT and sets table
 */
@@ -103,6 +113,15 @@ func (sql *postgres) Where(where map[string]interface{}) Builder {
 	return sql
 }
 
+/*
+WhereCond - conditions composed with And/Or/Not for SELECT/UPDATE/DELETE.
+Joined by AND with conditions from Where
+*/
+func (sql *postgres) WhereCond(conditions ...Condition) Builder {
+	sql.parts.conditions = append(sql.parts.conditions, conditions...)
+	return sql
+}
+
 /*
 Join - join source with params into query.
 Every table in SQL query have to have Alias. If you'll not provide - it will be generated
@@ -159,12 +178,38 @@ func (sql *postgres) OnConflictConstraint(constraint string) Builder {
 }
 
 /*
-Build - method that builds from params into SQL string
+Build - method that builds from params into SQL string.
+Values are written into SQL text, so use BuildWithArgs for user provided data.
+Returns empty string if names of tables or columns are not valid
 */
 func (sql postgres) Build() string {
+	if sql.parts.validate() != nil {
+		return ""
+	}
+	sql.inline = true
+	return sql.build()
+}
+
+/*
+BuildWithArgs - builds SQL string with placeholders and returns values as arguments for it.
+Names of tables and columns are checked, IdentifierError is returned for names that are not valid
+*/
+func (sql postgres) BuildWithArgs() (string, []interface{}, error) {
+	if err := sql.parts.validate(); err != nil {
+		return "", nil, err
+	}
+	sql.args = nil
+	SQL := sql.build()
+	return SQL, sql.args, nil
+}
+
+func (sql *postgres) build() string {
 	if sql.queryType == queryTypeSelect {
 		return sql.buildSelect()
 	}
+	if sql.queryType == queryTypeCount {
+		return sql.buildCount()
+	}
 	if sql.queryType == queryTypeInsert {
 		return sql.buildInsert()
 	}
@@ -212,9 +257,10 @@ func (sql *postgres) buildValues() string {
 	var values []string
 
 	if data, ok := sql.parts.insertData.(map[string]interface{}); ok {
-		for key, value := range data {
+		for _, key := range sortedKeys(data) {
+			value := data[key]
 			keys = append(keys, ""+key+"")
-			values = append(values, toString(value))
+			values = append(values, sql.bind(value))
 		}
 	}
 	return "(" + strings.Join(keys, ",") + ") VALUES (" + strings.Join(values, ",") + ")"
@@ -231,6 +277,14 @@ func (sql *postgres) buildSelect() (SQL string) {
 	return
 }
 
+func (sql *postgres) buildCount() (SQL string) {
+	SQL = queryTypeSelect + " COUNT(*)"
+	SQL += sql.buildFrom(true)
+	SQL += sql.buildJoin()
+	SQL += sql.buildWhere()
+	return
+}
+
 func (sql *postgres) buildFrom(alias bool) string {
 	return " FROM " + sql.buildTable(alias)
 }
@@ -269,48 +323,32 @@ func (sql *postgres) buildJoin() (join string) {
 }
 
 func (sql *postgres) buildWhere() (where string) {
-	if len(sql.parts.where) == 0 {
+	conditions := append([]Condition{Map(sql.parts.where)}, sql.parts.conditions...)
+	where = And(conditions...).render(sql, sql.column)
+	if where == "" {
 		return
 	}
-	where = " WHERE "
-	var w []string
-	for key, value := range sql.parts.where {
-		if sl, ok := value.([]int64); ok {
-			var str []string
-			for _, st := range sl {
-				str = append(str, strconv.FormatInt(st, 10))
-			}
-			w = append(w, sql.getAliasBySource(sql.parts.table)+"."+key+" IN ("+strings.Join(str, ",")+")")
-			continue
-		}
-		if sl, ok := value.([]string); ok {
-			var str []string
-			for _, st := range sl {
-				str = append(str, `'`+st+`'`)
-			}
-			w = append(w, sql.getAliasBySource(sql.parts.table)+"."+key+" IN ("+strings.Join(str, ",")+")")
-			continue
-		}
-		str := toString(value)
-		sign := ""
-		if strings.Index(key, "=") == -1 && strings.Index(key, ">") == -1 && strings.Index(key, "<") == -1 {
-			sign = "="
-		}
-		w = append(w, sql.getAliasBySource(sql.parts.table)+"."+key+sign+str)
+	return " WHERE " + where
+}
+
+// column - adding main table alias to column if it has no alias
+func (sql *postgres) column(name string) string {
+	if strings.Contains(name, ".") {
+		return name
 	}
-	return where + strings.Join(w, " AND ")
+	return sql.getAliasBySource(sql.parts.table) + "." + name
 }
 
 func (sql *postgres) buildSetter() (where string) {
-	if len(sql.parts.where) == 0 {
+	if len(sql.parts.where) == 0 && len(sql.parts.conditions) == 0 {
 		return
 	}
 	where = " SET "
 	var w []string
 	if data, ok := sql.parts.insertData.(map[string]interface{}); ok {
-		for key, value := range data {
-			str := toString(value)
-			w = append(w, ""+key+" = "+str)
+		for _, key := range sortedKeys(data) {
+			value := data[key]
+			w = append(w, ""+key+" = "+sql.bind(value))
 		}
 	}
 	return where + strings.Join(w, ", ")
@@ -378,9 +416,9 @@ func (sql *postgres) buildOnConflictAction() (conflict string) {
 
 			var w []string
 			if data, ok := sql.parts.insertData.(map[string]interface{}); ok {
-				for key, value := range data {
-					str := toString(value)
-					w = append(w, ""+key+" = "+str)
+				for _, key := range sortedKeys(data) {
+					value := data[key]
+					w = append(w, ""+key+" = "+sql.bind(value))
 				}
 			}
 			conflict += strings.Join(w, ", ") + " RETURNING *"
@@ -401,6 +439,28 @@ func (sql *postgres) checkOnConflictParts() bool {
 	return false
 }
 
+// bind - returns placeholder for value and saves value as query argument
+func (sql *postgres) bind(value interface{}) string {
+	if sql.inline {
+		return toString(value)
+	}
+	sql.args = append(sql.args, value)
+	return "$" + strconv.Itoa(len(sql.args))
+}
+
+// bindList - returns list of placeholders for IN statement
+func (sql *postgres) bindList(values []interface{}) string {
+	var list []string
+	for _, v := range values {
+		list = append(list, sql.bind(v))
+	}
+	return "(" + strings.Join(list, ",") + ")"
+}
+
+func (sql *postgres) ilike() string {
+	return "ILIKE"
+}
+
 func (sql *postgres) addToSources(table, id string) {
 	if sql.sources == nil {
 		sql.sources = make(map[string]string)
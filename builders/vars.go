@@ -15,6 +15,46 @@ const (
 	queryTypeSave   = "SAVE"
 	queryTypeUpdate = "UPDATE"
 	queryTypeDelete = "DELETE"
+	queryTypeCount  = "COUNT"
 	tablePrefix     = "t"
 	defaultLimit    = 100
 )
+
+// Filter operators that can be added to Where key with FilterSeparator: amount__gte
+const (
+	// FilterSeparator - separates column and operator in Where key
+	FilterSeparator = "__"
+	// FilterEq - column = value
+	FilterEq = "eq"
+	// FilterNe - column <> value
+	FilterNe = "ne"
+	// FilterGt - column > value
+	FilterGt = "gt"
+	// FilterGte - column >= value
+	FilterGte = "gte"
+	// FilterLt - column < value
+	FilterLt = "lt"
+	// FilterLte - column <= value
+	FilterLte = "lte"
+	// FilterLike - column LIKE value
+	FilterLike = "like"
+	// FilterILike - case insensitive LIKE
+	FilterILike = "ilike"
+	// FilterIn - column IN (values)
+	FilterIn = "in"
+	// FilterNotIn - column NOT IN (values)
+	FilterNotIn = "nin"
+	// FilterIsNull - column IS NULL for true and IS NOT NULL for false
+	FilterIsNull = "isnull"
+	// FilterBetween - column BETWEEN first value AND second value
+	FilterBetween = "between"
+)
+
+var comparisons = map[string]string{
+	FilterEq:  "=",
+	FilterNe:  "<>",
+	FilterGt:  ">",
+	FilterGte: ">=",
+	FilterLt:  "<",
+	FilterLte: "<=",
+}
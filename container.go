@@ -0,0 +1,139 @@
+package vodka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+/*
+Key - typed key of request values and dependencies. Keys are compared by pointer,
+so keys with the same name from different packages don't collide:
+
+	var CurrentUser = vodka.NewKey("currentUser")
+*/
+type Key struct {
+	name string
+}
+
+// NewKey - key constructor. Name is used only in errors and logs
+func NewKey(name string) *Key {
+	return &Key{name: name}
+}
+
+func (k *Key) String() string {
+	return k.name
+}
+
+/*
+SetValue - setting request-scoped value. Values are saved in Context(),
+so they are available in services and repositories bound to it
+*/
+func (ctx *Context) SetValue(key *Key, value interface{}) {
+	ctx.SetContext(context.WithValue(ctx.Context(), key, value))
+}
+
+// Value - getting request-scoped value. Nil if value is not set
+func (ctx *Context) Value(key *Key) interface{} {
+	return ctx.Context().Value(key)
+}
+
+// LookupValue - getting request-scoped value and flag that it is set
+func (ctx *Context) LookupValue(key *Key) (interface{}, bool) {
+	v := ctx.Context().Value(key)
+	return v, v != nil
+}
+
+/*
+Resolve - getting dependency registered in application with Provide
+*/
+func (ctx *Context) Resolve(key *Key) (interface{}, bool) {
+	if ctx.container == nil {
+		return nil, false
+	}
+	return ctx.container.Resolve(key)
+}
+
+/*
+Container - dependencies of application: adapters, services and other modules
+*/
+type Container struct {
+	mu    sync.RWMutex
+	deps  map[*Key]interface{}
+	order []*Key
+}
+
+/*
+Provide - registering dependency. Registering the same key twice panics:
+it is an error of application setup
+*/
+func (c *Container) Provide(key *Key, dep interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.deps == nil {
+		c.deps = make(map[*Key]interface{})
+	}
+	if _, ok := c.deps[key]; ok {
+		panic(fmt.Sprintf("vodka: dependency %s is already provided", key))
+	}
+	c.deps[key] = dep
+	c.order = append(c.order, key)
+}
+
+// Resolve - getting dependency and flag that it is registered
+func (c *Container) Resolve(key *Key) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	dep, ok := c.deps[key]
+	return dep, ok
+}
+
+// MustResolve - getting dependency. Panics if it is not registered
+func (c *Container) MustResolve(key *Key) interface{} {
+	dep, ok := c.Resolve(key)
+	if !ok {
+		panic(fmt.Sprintf("vodka: dependency %s is not provided", key))
+	}
+	return dep
+}
+
+/*
+Close - closing dependencies that have Close() error method (adapters)
+in reverse order of registration. Returns first error
+*/
+func (c *Container) Close() (err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i := len(c.order) - 1; i >= 0; i-- {
+		closer, ok := c.deps[c.order[i]].(interface{ Close() error })
+		if !ok {
+			continue
+		}
+		if closeErr := closer.Close(); closeErr != nil {
+			log.Printf("Closing %s error: %v", c.order[i], closeErr)
+			if err == nil {
+				err = closeErr
+			}
+		}
+	}
+	return
+}
+
+/*
+Provide - registering dependency of application. Dependencies can be resolved
+in handlers with ctx.Resolve and are closed on shutdown after OnShutdown hooks
+*/
+func (e *Application) Provide(key *Key, dep interface{}) {
+	e.container.Provide(key, dep)
+}
+
+// Resolve - getting dependency registered with Provide
+func (e *Application) Resolve(key *Key) (interface{}, bool) {
+	return e.container.Resolve(key)
+}
+
+// MustResolve - getting dependency registered with Provide. Panics if it is not registered
+func (e *Application) MustResolve(key *Key) interface{} {
+	return e.container.MustResolve(key)
+}
@@ -0,0 +1,125 @@
+package vodka
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContextValues(t *testing.T) {
+	user := NewKey("user")
+	other := NewKey("user")
+	ctx := &Context{Request: httptest.NewRequest("GET", "/", nil)}
+	if v, ok := ctx.LookupValue(user); ok || v != nil {
+		t.Errorf("value is set before SetValue: %v", v)
+	}
+	ctx.SetValue(user, "alice")
+	if ctx.Value(user) != "alice" {
+		t.Errorf("unexpected value: %v", ctx.Value(user))
+	}
+	// Keys with the same name don't collide
+	if v, ok := ctx.LookupValue(other); ok {
+		t.Errorf("value of other key: %v", v)
+	}
+	// Values are available in context passed to services
+	if ctx.Context().Value(user) != "alice" {
+		t.Error("value is not saved in Context()")
+	}
+	if user.String() != "user" {
+		t.Errorf("unexpected key name: %s", user)
+	}
+}
+
+func TestValueOfMiddlewareIsAvailableInHandler(t *testing.T) {
+	app := newTestApp()
+	user := NewKey("user")
+	app.Use(func(ctx *Context) (*Context, error) {
+		ctx.SetValue(user, "alice")
+		ctx.Next(ctx)
+		return ctx, nil
+	})
+	var got interface{}
+	app.Router.GET("/me", func(ctx *Context) (interface{}, error) {
+		got = ctx.Value(user)
+		return got, nil
+	})
+	serve(app, httptest.NewRequest("GET", "/me", nil))
+	if got != "alice" {
+		t.Errorf("unexpected value in handler: %v", got)
+	}
+}
+
+func TestContainer(t *testing.T) {
+	var c Container
+	db := NewKey("db")
+	c.Provide(db, "postgres")
+	if dep, ok := c.Resolve(db); !ok || dep != "postgres" {
+		t.Errorf("Resolve = %v, %v", dep, ok)
+	}
+	if dep, ok := c.Resolve(NewKey("db")); ok {
+		t.Errorf("dependency of other key: %v", dep)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic when key is provided twice")
+			}
+		}()
+		c.Provide(db, "mysql")
+	}()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic when key is not provided")
+			}
+		}()
+		c.MustResolve(NewKey("cache"))
+	}()
+}
+
+// failingCloser - dependency that fails to close
+type failingCloser struct {
+	err error
+}
+
+func (c failingCloser) Close() error {
+	return c.err
+}
+
+func TestContainerClose(t *testing.T) {
+	var c Container
+	var closed []string
+	errCache := errors.New("cache is not closed")
+	c.Provide(NewKey("db"), testCloser{name: "db", closed: &closed})
+	c.Provide(NewKey("config"), "not closer")
+	c.Provide(NewKey("cache"), failingCloser{err: errCache})
+	c.Provide(NewKey("service"), testCloser{name: "service", closed: &closed})
+
+	if err := c.Close(); err != errCache {
+		t.Errorf("expected error of cache, got %v", err)
+	}
+	if len(closed) != 2 || closed[0] != "service" || closed[1] != "db" {
+		t.Errorf("dependencies are not closed in reverse order: %v", closed)
+	}
+}
+
+func TestResolveFromContext(t *testing.T) {
+	app := newTestApp()
+	repo := NewKey("repo")
+	app.Provide(repo, "users")
+	if app.MustResolve(repo) != "users" {
+		t.Error("dependency is not resolved from application")
+	}
+	var got interface{}
+	app.Router.GET("/users", func(ctx *Context) (interface{}, error) {
+		got, _ = ctx.Resolve(repo)
+		return nil, nil
+	})
+	serve(app, httptest.NewRequest("GET", "/users", nil))
+	if got != "users" {
+		t.Errorf("dependency is not resolved from context: %v", got)
+	}
+	if dep, ok := (&Context{}).Resolve(repo); ok || dep != nil {
+		t.Errorf("context without container resolves %v", dep)
+	}
+}
@@ -1,6 +1,10 @@
 package vodka
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"time"
+)
 
 /*
 Context - context that contains information about request/response and middlewares results
@@ -19,6 +23,33 @@ type Context struct {
 	Request     *http.Request
 	Writer      http.ResponseWriter
 	Validation  methodRules
+	middlewares []Middleware
+	hooks       []Hook
+	ctx         context.Context
+	timeout     time.Duration
+	container   *Container
+	types       customTypes
+	// columns - columns of route model, unknown body fields are allowed only for them
+	columns map[string]bool
+}
+
+/*
+Context - context of request. It is done when client disconnects or route timeout is exceeded.
+Pass it to Service.WithContext, Recorder.WithContext or adapters to cancel queries
+*/
+func (ctx *Context) Context() context.Context {
+	if ctx.ctx != nil {
+		return ctx.ctx
+	}
+	if ctx.Request != nil {
+		return ctx.Request.Context()
+	}
+	return context.Background()
+}
+
+// SetContext - replacing context of request, e.g. with deadline set in middleware
+func (ctx *Context) SetContext(c context.Context) {
+	ctx.ctx = c
 }
 
 // RawContext - raw context struct to save raw data
@@ -35,7 +66,11 @@ type Validation struct {
 	Body   interface{}
 }
 
-// KeyStorage - main key storage for Validated and raw data
+/*
+KeyStorage - main key storage for Validated and raw data.
+Get<Type> methods panic if key is not set or value has other type,
+To<Type> and Get<Type>Or methods are safe and convert values
+*/
 type KeyStorage struct {
 	keys map[string]interface{}
 }
@@ -0,0 +1,74 @@
+package vodka
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestContextFallsBackToRequest(t *testing.T) {
+	ctx := &Context{}
+	if ctx.Context() != context.Background() {
+		t.Error("context without request is not background")
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	ctx.Request = req
+	if ctx.Context() != req.Context() {
+		t.Error("context of request is not used")
+	}
+	c, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	ctx.SetContext(c)
+	if ctx.Context() != c {
+		t.Error("context that is set is not used")
+	}
+}
+
+func TestRouteTimeout(t *testing.T) {
+	app := newTestApp()
+	loadTestRules(t, app, `{
+		"/reports/slow": {"get": {"options": {"timeout": "20ms"}}},
+		"/reports/seconds": {"get": {"options": {"timeout": 2}}}
+	}`)
+	deadlines := make(map[string]time.Duration)
+	handler := func(ctx *Context) (interface{}, error) {
+		deadline, ok := ctx.Context().Deadline()
+		if !ok {
+			return nil, nil
+		}
+		deadlines[ctx.Request.URL.Path] = time.Until(deadline)
+		if ctx.Request.URL.Path != "/reports/slow" {
+			return nil, nil
+		}
+		// Query that is cancelled by deadline
+		<-ctx.Context().Done()
+		return nil, ctx.Context().Err()
+	}
+	reports := app.Router.Timeout(time.Minute)
+	reports.GET("/reports/slow", handler)
+	reports.GET("/reports/seconds", handler)
+	reports.GET("/reports/minute", handler)
+	app.Router.GET("/users", handler)
+
+	w := serve(app, httptest.NewRequest("GET", "/reports/slow", nil))
+	if e := responseError(t, w); w.Code != http.StatusGatewayTimeout || e.Code != ErrTimeout.Code || e.Message != "Gateway timeout" {
+		t.Errorf("expected 504, got %d: %s", w.Code, w.Body.String())
+	}
+	for _, path := range []string{"/reports/seconds", "/reports/minute", "/users"} {
+		serve(app, httptest.NewRequest("GET", path, nil))
+	}
+	if d := deadlines["/reports/slow"]; d <= 0 || d > 20*time.Millisecond {
+		t.Errorf("timeout of option is not used: %v", d)
+	}
+	if d := deadlines["/reports/seconds"]; d <= time.Second || d > 2*time.Second {
+		t.Errorf("timeout in seconds is not used: %v", d)
+	}
+	if d := deadlines["/reports/minute"]; d <= 2*time.Second || d > time.Minute {
+		t.Errorf("timeout of router is not used: %v", d)
+	}
+	if _, ok := deadlines["/users"]; ok {
+		t.Error("route without timeout has deadline")
+	}
+}
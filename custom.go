@@ -0,0 +1,255 @@
+package vodka
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/syndicatedb/vodka/builders"
+)
+
+/*
+ValidatorFunc - validator of custom type. Returns typecasted value
+or error if value is not valid
+*/
+type ValidatorFunc func(value interface{}) (interface{}, error)
+
+// customTypes - custom types of application by name. Map is replaced on registration, so it is read without lock
+type customTypes map[string]ValidatorFunc
+
+/*
+RegisterValidator - registering custom type that can be used in rules: "type": "money".
+Custom types belong to application and can't override built-in ones
+*/
+func (e *Application) RegisterValidator(name string, fn ValidatorFunc) {
+	switch name {
+	case "int", "int64", "float", "float64", "bool", "string", TypeObject, TypeArray:
+		panic("vodka: validator can't override built-in type " + name)
+	}
+	if strings.HasPrefix(name, "[]") {
+		panic("vodka: validator name can't start with []: " + name)
+	}
+	e.validator.registerType(name, fn)
+}
+
+// registerType - adding custom type. Requests in progress keep previous types
+func (v *Validator) registerType(name string, fn ValidatorFunc) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	types := make(customTypes, len(v.types)+1)
+	for t, f := range v.types {
+		types[t] = f
+	}
+	types[name] = fn
+	v.types = types
+}
+
+// customTypes - current custom types
+func (v *Validator) customTypes() customTypes {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.types
+}
+
+// validateCustom - validating value by custom type
+func validateCustom(key string, value interface{}, t string, fn ValidatorFunc) (interface{}, error) {
+	res, err := fn(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s (%v) is not valid %s: %v", key, value, t, err)
+	}
+	return res, nil
+}
+
+/*
+fieldRule - cross-field rule of route:
+{"field": "endDate", "op": "gt", "other": "startDate"}.
+Fields are request names, location is body by default
+*/
+type fieldRule struct {
+	Field    string `json:"field"`
+	Operator string `json:"op"`
+	Other    string `json:"other"`
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// comparisonTexts - operators of cross-field rules
+var comparisonTexts = map[string]string{
+	builders.FilterEq:  "equal to",
+	builders.FilterNe:  "not equal to",
+	builders.FilterGt:  "greater than",
+	builders.FilterGte: "greater than or equal to",
+	builders.FilterLt:  "less than",
+	builders.FilterLte: "less than or equal to",
+}
+
+/*
+validateFieldRules - checking cross-field rules with validated values.
+Rule is skipped if one of fields is absent: presence is checked by "required"
+*/
+func validateFieldRules(rules []fieldRule, v methodRules, ctx *Context) (errs ValidationErrors) {
+	for _, r := range rules {
+		location := ruleLocation(r)
+		var vm map[string]validation
+		var ks *KeyStorage
+		switch location {
+		case LocationParams:
+			vm, ks = v.Params, &ctx.Params
+		case LocationQuery:
+			vm, ks = v.Query, &ctx.Query
+		default:
+			vm, ks = v.Body, &ctx.Body
+		}
+		a, okA := ruleValue(vm, ks, r.Field)
+		b, okB := ruleValue(vm, ks, r.Other)
+		if !okA || !okB {
+			continue
+		}
+		text, ok := comparisonTexts[r.Operator]
+		if !ok {
+			errs = append(errs, ruleFailed(location, r, a, fmt.Sprintf("%s: operator %s is not supported", r.Field, r.Operator)))
+			continue
+		}
+		cmp, ok := compareValues(a, b)
+		if !ok {
+			errs = append(errs, ruleFailed(location, r, a, fmt.Sprintf("%s (%v) can't be compared with %s (%v)", r.Field, a, r.Other, b)))
+			continue
+		}
+		if !comparisonResult(r.Operator, cmp) {
+			msg := r.Message
+			if msg == "" {
+				msg = fmt.Sprintf("%s must be %s %s", r.Field, text, r.Other)
+			}
+			errs = append(errs, ruleFailed(location, r, a, msg))
+		}
+	}
+	return
+}
+
+/*
+checkFieldRules - checking operators, locations and fields of cross-field rules on load,
+so mistake in validation file stops application and is not 400 on every request
+*/
+func checkFieldRules(v methodRules) error {
+	for _, r := range v.Rules {
+		if _, ok := comparisonTexts[r.Operator]; !ok {
+			return fmt.Errorf("rule of %s: operator %q is not supported", r.Field, r.Operator)
+		}
+		var vm map[string]validation
+		switch location := ruleLocation(r); location {
+		case LocationParams:
+			vm = v.Params
+		case LocationQuery:
+			vm = v.Query
+		case LocationBody:
+			vm = v.Body
+		default:
+			return fmt.Errorf("rule of %s: location %q is not supported", r.Field, r.Location)
+		}
+		for _, name := range []string{r.Field, r.Other} {
+			if _, _, ok := findRule(vm, name); !ok {
+				return fmt.Errorf("rule of %s: field %q is not defined in %s", r.Field, name, ruleLocation(r))
+			}
+		}
+	}
+	return nil
+}
+
+// ruleLocation - location of cross-field rule, body by default
+func ruleLocation(r fieldRule) string {
+	if r.Location == "" {
+		return LocationBody
+	}
+	return r.Location
+}
+
+func ruleFailed(location string, r fieldRule, value interface{}, msg string) FieldError {
+	return FieldError{
+		Location: location,
+		Field:    r.Field,
+		Expected: r.Operator + " " + r.Other,
+		Rule:     r.Operator,
+		Value:    value,
+		Message:  msg,
+	}
+}
+
+// ruleValue - validated value by request name
+func ruleValue(vm map[string]validation, ks *KeyStorage, name string) (interface{}, bool) {
+	key, _, ok := findRule(vm, name)
+	if !ok {
+		return nil, false
+	}
+	value := ks.Get(key)
+	return value, value != nil
+}
+
+func comparisonResult(operator string, cmp int) bool {
+	switch operator {
+	case builders.FilterEq:
+		return cmp == 0
+	case builders.FilterNe:
+		return cmp != 0
+	case builders.FilterGt:
+		return cmp > 0
+	case builders.FilterGte:
+		return cmp >= 0
+	case builders.FilterLt:
+		return cmp < 0
+	case builders.FilterLte:
+		return cmp <= 0
+	}
+	return false
+}
+
+/*
+compareValues - comparing numbers, times and strings. Strings in RFC3339 or YYYY-MM-DD
+are compared as times. Returns false if values can't be compared
+*/
+func compareValues(a, b interface{}) (int, bool) {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		return compareFloats(x, y), true
+	}
+	if x, ok := toTime(a); ok {
+		y, ok := toTime(b)
+		if !ok {
+			return 0, false
+		}
+		return compareFloats(float64(x.Sub(y)), 0), true
+	}
+	x, okA := a.(string)
+	y, okB := b.(string)
+	if !okA || !okB {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+func compareFloats(x, y float64) int {
+	if x < y {
+		return -1
+	}
+	if x > y {
+		return 1
+	}
+	return 0
+}
+
+func toTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, true
+		}
+		if t, err := time.Parse("2006-01-02", v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,165 @@
+package vodka
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// moneyValidator - amount in cents from "12.50"
+func moneyValidator(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("expected string")
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, errors.New("expected decimal")
+	}
+	return int64(f*100 + 0.5), nil
+}
+
+const moneyRules = `{
+	"/payments": {
+		"post": {"body": {"amount": {"type": "money", "required": true}}}
+	}
+}`
+
+func TestCustomTypesBelongToApplication(t *testing.T) {
+	handler := func(ctx *Context) (interface{}, error) { return ctx.Body.Get("amount"), nil }
+	withMoney := newTestApp()
+	withMoney.RegisterValidator("money", moneyValidator)
+	loadTestRules(t, withMoney, moneyRules)
+	withMoney.Router.POST("/payments", handler)
+	other := newTestApp()
+	loadTestRules(t, other, moneyRules)
+	other.Router.POST("/payments", handler)
+
+	w := serve(withMoney, httptest.NewRequest("POST", "/payments", strings.NewReader(`{"amount":"12.50"}`)))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"data":1250`) {
+		t.Errorf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	w = serve(withMoney, httptest.NewRequest("POST", "/payments", strings.NewReader(`{"amount":"ten"}`)))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "amount (ten) is not valid money: expected decimal") {
+		t.Errorf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	w = serve(other, httptest.NewRequest("POST", "/payments", strings.NewReader(`{"amount":"12.50"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("type of other application is used: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRegisterValidatorPanics(t *testing.T) {
+	for _, name := range []string{"int", "string", TypeObject, "[]money"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", name)
+				}
+			}()
+			New().RegisterValidator(name, moneyValidator)
+		}()
+	}
+}
+
+func TestFieldRules(t *testing.T) {
+	app := newTestApp()
+	loadTestRules(t, app, `{
+		"/bookings": {
+			"post": {
+				"body": {
+					"start_date": {"type": "string", "name": "startDate", "format": "date"},
+					"end_date": {"type": "string", "name": "endDate", "format": "date"},
+					"guests": {"type": "int"},
+					"rooms": {"type": "int"}
+				},
+				"rules": [
+					{"field": "endDate", "op": "gt", "other": "startDate"},
+					{"field": "rooms", "op": "lte", "other": "guests", "message": "too many rooms"}
+				]
+			}
+		}
+	}`)
+	app.Router.POST("/bookings", func(ctx *Context) (interface{}, error) { return nil, nil })
+
+	tests := []struct {
+		body    string
+		status  int
+		message string
+	}{
+		{`{"startDate":"2020-01-01","endDate":"2020-01-05","guests":2,"rooms":1}`, http.StatusOK, ""},
+		{`{"startDate":"2020-01-05","endDate":"2020-01-01"}`, http.StatusBadRequest, "endDate must be greater than startDate"},
+		{`{"guests":1,"rooms":2}`, http.StatusBadRequest, "too many rooms"},
+		{`{"startDate":"2020-01-05"}`, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		w := serve(app, httptest.NewRequest("POST", "/bookings", strings.NewReader(tt.body)))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d: %s", tt.body, tt.status, w.Code, w.Body.String())
+			continue
+		}
+		if tt.message != "" && !strings.Contains(w.Body.String(), `"message":"`+tt.message+`"`) {
+			t.Errorf("%s: expected %q, got %s", tt.body, tt.message, w.Body.String())
+		}
+	}
+}
+
+func TestCheckFieldRules(t *testing.T) {
+	body := map[string]validation{"start": {InputType: "string"}, "end_date": {InputType: "string", Name: "end"}}
+	tests := []struct {
+		rule fieldRule
+		err  string
+	}{
+		{fieldRule{Field: "end", Operator: "gt", Other: "start"}, ""},
+		{fieldRule{Field: "end", Operator: "after", Other: "start"}, `operator "after" is not supported`},
+		{fieldRule{Field: "end", Operator: "gt", Other: "start", Location: "header"}, `location "header" is not supported`},
+		{fieldRule{Field: "end", Operator: "gt", Other: "begin"}, `field "begin" is not defined in body`},
+		{fieldRule{Field: "end", Operator: "gt", Other: "start", Location: LocationQuery}, `field "end" is not defined in query`},
+	}
+	for _, tt := range tests {
+		err := checkFieldRules(methodRules{Body: body, Rules: []fieldRule{tt.rule}})
+		if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%+v: expected %q, got %v", tt.rule, tt.err, err)
+		}
+	}
+}
+
+func TestValidationFailsOnInvalidFieldRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "validation.json")
+	rules := `{"/bookings": {"post": {
+		"body": {"start": {"type": "string"}},
+		"rules": [{"field": "end", "op": "gt", "other": "start"}]
+	}}}`
+	if err := ioutil.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := newTestApp().Validation(path)
+	if err == nil || !strings.Contains(err.Error(), "validation.json: POST /bookings: rule of end") {
+		t.Errorf("expected error of rule with file and route, got %v", err)
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a, b interface{}
+		cmp  int
+		ok   bool
+	}{
+		{1, 2.5, -1, true},
+		{int64(3), 3, 0, true},
+		{"2020-01-02T00:00:00Z", "2020-01-01", 1, true},
+		{"b", "a", 1, true},
+		{1, "a", 0, false},
+		{true, false, 0, false},
+	}
+	for _, tt := range tests {
+		if cmp, ok := compareValues(tt.a, tt.b); cmp != tt.cmp || ok != tt.ok {
+			t.Errorf("compareValues(%v, %v) = %d, %v, want %d, %v", tt.a, tt.b, cmp, ok, tt.cmp, tt.ok)
+		}
+	}
+}
@@ -1,17 +1,121 @@
 package vodka
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"runtime"
+	"sync"
+
+	"github.com/syndicatedb/vodka/builders"
 )
 
 // Error - framework error type
 type Error struct {
 	httpCode int
+	err      error
+	Code     string      `json:"code,omitempty"`
 	Message  string      `json:"message"`
 	Stack    interface{} `json:"stack,omitempty"`
 	Info     interface{} `json:"info"`
 }
 
+/*
+ErrorCode - application error code with default HTTP status and message.
+Codes are used as sentinels: errors.Is(err, vodka.ErrNotFound)
+*/
+type ErrorCode struct {
+	Code    string
+	Status  int
+	Message string
+}
+
+// errorCodes - registered codes by name
+var errorCodes sync.Map
+
+// Built-in error codes
+var (
+	ErrBadRequest      = RegisterError("bad_request", ErrorBadRequestCode, "Bad request")
+	ErrUnauthorized    = RegisterError("unauthorized", ErrorUnathorizedCode, "Unauthorized")
+	ErrAccessDenied    = RegisterError("access_denied", ErrorAccessDeniedCode, "Access denied")
+	ErrNotFound        = RegisterError("not_found", ErrorNotFoundCode, "Not found")
+	ErrConflict        = RegisterError("conflict", ErrorConflictCode, "Conflict")
+	ErrRequestTooLarge = RegisterError("request_too_large", ErrorRequestTooLargeCode, "Request is too large")
+	ErrUnprocessable   = RegisterError("unprocessable", ErrorUnprocessableCode, "Unprocessable entity")
+	ErrTooManyRequests = RegisterError("too_many_requests", ErrorTooManyRequestsCode, "Too many requests")
+	ErrServerError     = RegisterError("server_error", ErrorServerErrorCode, "Internal server error")
+	ErrUnavailable     = RegisterError("unavailable", ErrorUnavailableCode, "Service unavailable")
+	ErrTimeout         = RegisterError("timeout", ErrorTimeoutCode, "Gateway timeout")
+)
+
+// errorMapping - errors that are sent with code when handler returns them
+type errorMapping struct {
+	target error
+	code   *ErrorCode
+}
+
+var (
+	mappingsMu    sync.RWMutex
+	errorMappings = []errorMapping{
+		{target: sql.ErrNoRows, code: ErrNotFound},
+		{target: builders.ErrInvalidIdentifier, code: ErrBadRequest},
+	}
+)
+
+/*
+RegisterError - registering application error code. Registering the same code twice panics
+*/
+func RegisterError(code string, status int, message string) *ErrorCode {
+	c := &ErrorCode{Code: code, Status: status, Message: message}
+	if _, loaded := errorCodes.LoadOrStore(code, c); loaded {
+		panic("vodka: error code is already registered: " + code)
+	}
+	return c
+}
+
+// LookupError - getting registered error code
+func LookupError(code string) (*ErrorCode, bool) {
+	c, ok := errorCodes.Load(code)
+	if !ok {
+		return nil, false
+	}
+	return c.(*ErrorCode), true
+}
+
+/*
+MapError - errors that match target with errors.Is and are not Error
+are sent with status and message of code: vodka.MapError(ErrOutOfStock, vodka.ErrConflict)
+*/
+func MapError(target error, code *ErrorCode) {
+	mappingsMu.Lock()
+	defer mappingsMu.Unlock()
+	errorMappings = append(errorMappings, errorMapping{target: target, code: code})
+}
+
+func (c *ErrorCode) Error() string {
+	return c.Message
+}
+
+// New - Error with code, default message and info
+func (c *ErrorCode) New(info interface{}) error {
+	return newError(c.Status, c.Code, c.Message, info, nil)
+}
+
+// Wrap - Error with code that wraps err, so errors.Is(res, err) is true
+func (c *ErrorCode) Wrap(err error, info interface{}) error {
+	return newError(c.Status, c.Code, c.Message, info, err)
+}
+
+/*
+NewCodeError - Error by registered code. Unknown code is sent with status 500
+*/
+func NewCodeError(code string, info interface{}) error {
+	if c, ok := LookupError(code); ok {
+		return c.New(info)
+	}
+	return newError(ErrorServerErrorCode, code, code, info, nil)
+}
+
 // NewServerError - 500 error decorator
 func NewServerError(message string, info interface{}) error {
 	return NewError(ErrorServerErrorCode, message, info)
@@ -27,21 +131,130 @@ func NewUnathorizedError(message string, info interface{}) error {
 	return NewError(ErrorUnathorizedCode, message, info)
 }
 
-// NewError - Error constructor
+// NewAccessDeniedError - 403 error decorator
+func NewAccessDeniedError(message string, info interface{}) error {
+	return NewError(ErrorAccessDeniedCode, message, info)
+}
+
+// NewNotFoundError - 404 error decorator
+func NewNotFoundError(message string, info interface{}) error {
+	return NewError(ErrorNotFoundCode, message, info)
+}
+
+// NewConflictError - 409 error decorator
+func NewConflictError(message string, info interface{}) error {
+	return NewError(ErrorConflictCode, message, info)
+}
+
+// NewPayloadTooLargeError - 413 error decorator
+func NewPayloadTooLargeError(message string, info interface{}) error {
+	return NewError(ErrorRequestTooLargeCode, message, info)
+}
+
+// NewUnprocessableError - 422 error decorator
+func NewUnprocessableError(message string, info interface{}) error {
+	return NewError(ErrorUnprocessableCode, message, info)
+}
+
+// NewTooManyRequestsError - 429 error decorator
+func NewTooManyRequestsError(message string, info interface{}) error {
+	return NewError(ErrorTooManyRequestsCode, message, info)
+}
+
+// NewUnavailableError - 503 error decorator
+func NewUnavailableError(message string, info interface{}) error {
+	return NewError(ErrorUnavailableCode, message, info)
+}
+
+// NewGatewayTimeoutError - 504 error decorator
+func NewGatewayTimeoutError(message string, info interface{}) error {
+	return NewError(ErrorTimeoutCode, message, info)
+}
+
+/*
+NewError - Error constructor. Code is the first built-in code with the same status
+*/
 func NewError(httpCode int, message string, info interface{}) error {
-	buf := make([]byte, 2048)
-	runtime.Stack(buf, true)
+	return newError(httpCode, statusCode(httpCode), message, info, nil)
+}
+
+/*
+WrapError - Error that wraps err, so errors.Is and errors.As see original error
+*/
+func WrapError(httpCode int, err error, message string, info interface{}) error {
+	return newError(httpCode, statusCode(httpCode), message, info, err)
+}
+
+func newError(httpCode int, code, message string, info interface{}, err error) Error {
 	e := Error{
 		httpCode: httpCode,
+		err:      err,
+		Code:     code,
 		Message:  message,
 		Info:     info,
 	}
 	if isDebug {
+		buf := make([]byte, 2048)
+		runtime.Stack(buf, true)
 		e.Stack = string(buf)
 	}
 	return e
 }
 
+// statusCode - code of built-in error by HTTP status
+func statusCode(httpCode int) string {
+	for _, c := range []*ErrorCode{
+		ErrBadRequest, ErrUnauthorized, ErrAccessDenied, ErrNotFound, ErrConflict, ErrRequestTooLarge,
+		ErrUnprocessable, ErrTooManyRequests, ErrServerError, ErrUnavailable, ErrTimeout,
+	} {
+		if c.Status == httpCode {
+			return c.Code
+		}
+	}
+	return ""
+}
+
 func (e Error) Error() string {
+	if e.err != nil && e.err.Error() != e.Message {
+		return fmt.Sprintf("%s: %v", e.Message, e.err)
+	}
 	return e.Message
 }
+
+// Status - HTTP status of error
+func (e Error) Status() int {
+	return e.httpCode
+}
+
+// Unwrap - wrapped error or nil
+func (e Error) Unwrap() error {
+	return e.err
+}
+
+// Is - Error matches ErrorCode with the same code
+func (e Error) Is(target error) bool {
+	c, ok := target.(*ErrorCode)
+	return ok && e.Code != "" && e.Code == c.Code
+}
+
+/*
+toError - Error that is sent for err: Error from chain of wrapped errors
+or Error by mapping, otherwise err as is
+*/
+func toError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e Error
+	if errors.As(err, &e) {
+		return e
+	}
+	mappingsMu.RLock()
+	defer mappingsMu.RUnlock()
+	for _, m := range errorMappings {
+		if errors.Is(err, m.target) {
+			return m.code.Wrap(err, err.Error())
+		}
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+package vodka
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/syndicatedb/vodka/builders"
+)
+
+func TestErrorConstructors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   *ErrorCode
+	}{
+		{NewBadRequestError("m", nil), http.StatusBadRequest, ErrBadRequest},
+		{NewUnathorizedError("m", nil), http.StatusUnauthorized, ErrUnauthorized},
+		{NewAccessDeniedError("m", nil), http.StatusForbidden, ErrAccessDenied},
+		{NewNotFoundError("m", nil), http.StatusNotFound, ErrNotFound},
+		{NewConflictError("m", nil), http.StatusConflict, ErrConflict},
+		{NewPayloadTooLargeError("m", nil), http.StatusRequestEntityTooLarge, ErrRequestTooLarge},
+		{NewUnprocessableError("m", nil), http.StatusUnprocessableEntity, ErrUnprocessable},
+		{NewTooManyRequestsError("m", nil), http.StatusTooManyRequests, ErrTooManyRequests},
+		{NewServerError("m", nil), http.StatusInternalServerError, ErrServerError},
+		{NewUnavailableError("m", nil), http.StatusServiceUnavailable, ErrUnavailable},
+		{NewGatewayTimeoutError("m", nil), http.StatusGatewayTimeout, ErrTimeout},
+	}
+	for _, tt := range tests {
+		e, ok := tt.err.(Error)
+		if !ok || e.Status() != tt.status || e.Code != tt.code.Code || e.Message != "m" {
+			t.Errorf("%s: unexpected error %#v", tt.code.Code, tt.err)
+		}
+		if !errors.Is(tt.err, tt.code) {
+			t.Errorf("%s: errors.Is is false", tt.code.Code)
+		}
+	}
+	if e := NewError(418, "teapot", nil).(Error); e.Code != "" || e.Status() != 418 {
+		t.Errorf("unexpected error of unknown status: %#v", e)
+	}
+}
+
+// errTestOutOfStock - application code registered once, so tests can be repeated
+var errTestOutOfStock = RegisterError("test_out_of_stock", http.StatusConflict, "Out of stock")
+
+func TestErrorCodes(t *testing.T) {
+	if c, ok := LookupError("test_out_of_stock"); !ok || c != errTestOutOfStock {
+		t.Errorf("code is not registered: %v", c)
+	}
+	err := NewCodeError("test_out_of_stock", map[string]int{"sku": 1})
+	var e Error
+	if !errors.As(err, &e) || e.Status() != http.StatusConflict || e.Message != "Out of stock" {
+		t.Errorf("unexpected error of code: %#v", err)
+	}
+	if !errors.Is(err, errTestOutOfStock) || errors.Is(err, ErrConflict) {
+		t.Error("error matches wrong code")
+	}
+	if e := NewCodeError("test_unknown", nil).(Error); e.Status() != http.StatusInternalServerError || e.Code != "test_unknown" {
+		t.Errorf("unexpected error of unknown code: %#v", e)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when code is registered twice")
+		}
+	}()
+	RegisterError("test_out_of_stock", http.StatusConflict, "Out of stock")
+}
+
+func TestErrorWrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("find user: %w", ErrUnavailable.Wrap(cause, nil))
+	if !errors.Is(err, cause) || !errors.Is(err, ErrUnavailable) {
+		t.Error("wrapped error is not found")
+	}
+	var e Error
+	if !errors.As(err, &e) || e.Error() != "Service unavailable: connection refused" {
+		t.Errorf("unexpected error: %v", e)
+	}
+	if e := WrapError(http.StatusNotFound, sql.ErrNoRows, "user_not_found", nil).(Error); e.Unwrap() != sql.ErrNoRows || e.Code != ErrNotFound.Code {
+		t.Errorf("unexpected wrapped error: %#v", e)
+	}
+}
+
+func TestToError(t *testing.T) {
+	errOutOfStock := errors.New("test: out of stock")
+	MapError(errOutOfStock, ErrConflict)
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{sql.ErrNoRows, http.StatusNotFound},
+		{fmt.Errorf("find: %w", sql.ErrNoRows), http.StatusNotFound},
+		{&builders.IdentifierError{Part: "column", Name: "x y"}, http.StatusBadRequest},
+		{fmt.Errorf("order: %w", errOutOfStock), http.StatusConflict},
+		{fmt.Errorf("wrapped: %w", NewAccessDeniedError("m", nil)), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		e, ok := toError(tt.err).(Error)
+		if !ok || e.Status() != tt.status {
+			t.Errorf("%v: expected %d, got %#v", tt.err, tt.status, toError(tt.err))
+		}
+	}
+	plain := errors.New("plain")
+	if toError(plain) != plain || toError(nil) != nil {
+		t.Error("unmapped error is changed")
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	app := newTestApp()
+	app.Router.GET("/users/:id", func(ctx *Context) (interface{}, error) {
+		switch ctx.Raw.Params.Get("id") {
+		case "missing":
+			return nil, fmt.Errorf("user: %w", sql.ErrNoRows)
+		case "plain":
+			return nil, errors.New("driver failure")
+		}
+		return nil, ErrAccessDenied.New("not owner")
+	})
+	tests := []struct {
+		id     string
+		status int
+		code   string
+	}{
+		{"missing", http.StatusNotFound, ErrNotFound.Code},
+		{"plain", http.StatusInternalServerError, ""},
+		{"other", http.StatusForbidden, ErrAccessDenied.Code},
+	}
+	for _, tt := range tests {
+		w := serve(app, httptest.NewRequest("GET", "/users/"+tt.id, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.id, tt.status, w.Code)
+			continue
+		}
+		if tt.code != "" && responseError(t, w).Code != tt.code {
+			t.Errorf("%s: expected code %s, got %s", tt.id, tt.code, w.Body.String())
+		}
+	}
+}
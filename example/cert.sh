@@ -0,0 +1,6 @@
+#!/bin/bash
+# Self-signed certificate for local HTTPS: set tlsCert/tlsKey in config.json to ./certs/localhost.crt and ./certs/localhost.key
+	mkdir -p certs && \
+	openssl req -x509 -newkey rsa:2048 -nodes -days 365 \
+		-keyout certs/localhost.key -out certs/localhost.crt \
+		-subj "/CN=localhost" -addext "subjectAltName=DNS:localhost,IP:127.0.0.1"
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"github.com/syndicatedb/vodka"
 	"github.com/syndicatedb/vodka/base"
 	"github.com/syndicatedb/vodka/example/modules/orders"
 )
@@ -8,11 +9,25 @@ import (
 // Order - users controller struct
 type Order struct {
 	base.Controller
+	module *orders.API
 }
 
 // NewOrders - users constructors
 func NewOrders(m *orders.API) *Order {
 	return &Order{
 		Controller: base.NewController(m),
+		module:     m,
 	}
 }
+
+// Create - creating order from typed request
+func (c *Order) Create(ctx *vodka.Context) (interface{}, error) {
+	var req orders.CreateOrder
+	if err := ctx.Bind(&req); err != nil {
+		return nil, err
+	}
+	return c.module.BindContext(ctx.Context()).Create(map[string]interface{}{
+		"name":   req.Name,
+		"status": req.Status,
+	})
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"log"
+	"os"
+	"time"
 
 	"github.com/syndicatedb/vodka/example/modules/items"
 
@@ -12,52 +14,59 @@ import (
 	"github.com/syndicatedb/vodka/example/modules/users"
 )
 
-type infrastructure struct {
-	Postgres adapters.Adapter
-	MySQL    adapters.Adapter
-}
+// Dependencies of application
+var (
+	postgresKey = vodka.NewKey("postgres")
+	mysqlKey    = vodka.NewKey("mysql")
+)
 
-var repos infrastructure
 var config Config
 var err error
 
-var userModule *users.API
-var orderModule *orders.API
-var itemsModule *items.API
-
 func init() {
 	if config, err = NewConfig("./config.json"); err != nil {
 		log.Fatalln("Error reading config: ", err)
 	}
-	repos.Postgres = adapters.NewPostgres(config.Postgres)
-	repos.MySQL = adapters.NewMySQL(config.MySQL)
-
-	userModule = users.New(repos.Postgres)
-	orderModule = orders.New(repos.MySQL)
-	itemsModule = items.New(repos.Postgres)
 }
 
 func main() {
 	engine := vodka.New()
 	engine.Server(config.HTTPServer)
+	// Adapters are closed on shutdown by container
+	engine.Provide(postgresKey, adapters.NewPostgres(config.Postgres))
+	engine.Provide(mysqlKey, adapters.NewMySQL(config.MySQL))
 	engine.Validation("./validation.json")
+	if os.Getenv("DEBUG") == "true" {
+		// Rules are reloaded without restart, see run.sh
+		if err := engine.WatchValidation(time.Second); err != nil {
+			log.Fatalln("Error watching validation: ", err)
+		}
+	}
+
+	postgres := engine.MustResolve(postgresKey).(adapters.Adapter)
+	mysql := engine.MustResolve(mysqlKey).(adapters.Adapter)
 
-	userCtrl := controllers.NewUsers(userModule)
-	orderCtrl := controllers.NewOrders(orderModule)
-	itemsCtrl := controllers.NewItems(itemsModule)
+	userCtrl := controllers.NewUsers(users.New(postgres))
+	orderCtrl := controllers.NewOrders(orders.New(mysql))
+	itemsCtrl := controllers.NewItems(items.New(postgres))
 
-	engine.Router.GET("/users", userCtrl.Find)
-	engine.Router.GET("/users/:id", userCtrl.FindByID)
-	engine.Router.POST("/users", userCtrl.Create)
-	engine.Router.PUT("/users/:id", userCtrl.UpdateByID)
+	engine.Router.Resource("/users", userCtrl, vodka.ResourceOptions{
+		Except: []string{vodka.ActionSave},
+		Model:  users.User{},
+	})
 	engine.Router.PUT("/users", userCtrl.Update)
-	engine.Router.DELETE("/users/:id", userCtrl.DeleteByID)
 
 	engine.Router.GET("/orders", orderCtrl.Find)
+	engine.Router.Request(orders.CreateOrder{}).POST("/orders", orderCtrl.Create)
 
 	engine.Router.POST("/items", itemsCtrl.Save)
 
-	for {
-		engine.Start()
+	engine.Router.ServeOpenAPI("/openapi.json", vodka.OpenAPIInfo{
+		Title:   "Vodka example",
+		Version: config.Version,
+	})
+
+	if err := engine.Start(); err != nil {
+		log.Fatalln("Server error: ", err)
 	}
 }
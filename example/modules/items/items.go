@@ -1,6 +1,7 @@
 package items
 
 import (
+	"context"
 	"time"
 
 	"github.com/syndicatedb/vodka/adapters"
@@ -33,3 +34,8 @@ func New(adapter adapters.Adapter) *API {
 		Service: base.NewService(repo),
 	}
 }
+
+// BindContext - module bound to context of request, used by base.Controller
+func (a *API) BindContext(ctx context.Context) base.Service {
+	return &API{Service: a.Service.WithContext(ctx)}
+}
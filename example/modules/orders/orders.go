@@ -1,6 +1,8 @@
 package orders
 
 import (
+	"context"
+
 	"github.com/syndicatedb/vodka/adapters"
 	"github.com/syndicatedb/vodka/base"
 	"github.com/syndicatedb/vodka/repositories"
@@ -20,6 +22,12 @@ type Order struct {
 	Status string `db:"status" json:"status"`
 }
 
+// CreateOrder — request of order creation. Validation rules are derived from tags
+type CreateOrder struct {
+	Name   string `json:"name" validate:"required,minLength=2,maxLength=100"`
+	Status string `json:"status" validate:"enum=new|paid|cancelled,default=new"`
+}
+
 // New - module constructor
 func New(adapter adapters.Adapter) *API {
 	var u Order
@@ -28,3 +36,8 @@ func New(adapter adapters.Adapter) *API {
 		Service: base.NewService(repo),
 	}
 }
+
+// BindContext - module bound to context of request, used by base.Controller
+func (a *API) BindContext(ctx context.Context) base.Service {
+	return &API{Service: a.Service.WithContext(ctx)}
+}
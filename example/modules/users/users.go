@@ -1,6 +1,7 @@
 package users
 
 import (
+	"context"
 	"time"
 
 	"github.com/syndicatedb/vodka/adapters"
@@ -32,3 +33,8 @@ func New(adapter adapters.Adapter) *API {
 		Service: base.NewService(repo),
 	}
 }
+
+// BindContext - module bound to context of request, used by base.Controller
+func (a *API) BindContext(ctx context.Context) base.Service {
+	return &API{Service: a.Service.WithContext(ctx)}
+}
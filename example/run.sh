@@ -1,4 +1,4 @@
 #!/bin/bash
 	go get && \
 	go get github.com/cespare/reflex && \
-	reflex -r '\.go|.json$' -s -- sh -c 'go build -o api && DEBUG=true ./api'; \
\ No newline at end of file
+	reflex -r '\.go$|config\.json$' -s -- sh -c 'go build -o api && DEBUG=true ./api'; \
\ No newline at end of file
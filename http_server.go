@@ -1,9 +1,16 @@
 package vodka
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
 	"strconv"
+	"sync"
+	"time"
 )
 
 const (
@@ -18,6 +25,20 @@ const (
 	ErrorServerErrorCode = 500
 	// ErrorAccessDeniedCode - server HTTP code for ServerError 403
 	ErrorAccessDeniedCode = 403
+	// ErrorNotFoundCode - server HTTP code for NotFound 404
+	ErrorNotFoundCode = 404
+	// ErrorConflictCode - server HTTP code for Conflict 409
+	ErrorConflictCode = 409
+	// ErrorUnprocessableCode - server HTTP code for UnprocessableEntity 422
+	ErrorUnprocessableCode = 422
+	// ErrorTooManyRequestsCode - server HTTP code for TooManyRequests 429
+	ErrorTooManyRequestsCode = 429
+	// ErrorUnavailableCode - server HTTP code for ServiceUnavailable 503
+	ErrorUnavailableCode = 503
+	// ErrorRequestTooLargeCode - server HTTP code for body larger than MaxBodySize 413
+	ErrorRequestTooLargeCode = 413
+	// ErrorTimeoutCode - server HTTP code for request that exceeded route timeout 504
+	ErrorTimeoutCode = 504
 	// StatusOK - response with code 200
 	StatusOK = 200
 	// StatusNoContent - response with code 204
@@ -28,11 +49,107 @@ const (
 type ResponseNoContent struct {
 }
 
-// HTTPConfig - HTTP server config
+// Defaults for HTTPConfig
+const (
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultReadTimeout       = 30 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultMaxBodySize       = 10 << 20
+)
+
+/*
+HTTPConfig - HTTP server config. Zero values are replaced with defaults by Application.Server.
+In JSON timeouts are seconds (15) or duration strings ("500ms").
+WriteTimeout cuts off response of route with longer timeout, such routes are logged on Start
+*/
 type HTTPConfig struct {
 	Host        string
 	Port        int
 	ContentType string
+
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// MaxBodySize - max request body in bytes. -1 for unlimited
+	MaxBodySize int64
+	// TLSCert, TLSKey - paths to certificate and key files. Server uses HTTPS if both are set
+	TLSCert string
+	TLSKey  string
+	// Socket - path to Unix domain socket to listen instead of Host:Port
+	Socket string
+}
+
+// UnmarshalJSON - decoding config with timeouts in seconds or duration strings
+func (c *HTTPConfig) UnmarshalJSON(data []byte) error {
+	type config HTTPConfig
+	raw := struct {
+		*config
+		ReadHeaderTimeout jsonDuration
+		ReadTimeout       jsonDuration
+		WriteTimeout      jsonDuration
+		IdleTimeout       jsonDuration
+	}{
+		config:            (*config)(c),
+		ReadHeaderTimeout: jsonDuration(c.ReadHeaderTimeout),
+		ReadTimeout:       jsonDuration(c.ReadTimeout),
+		WriteTimeout:      jsonDuration(c.WriteTimeout),
+		IdleTimeout:       jsonDuration(c.IdleTimeout),
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c.ReadHeaderTimeout = time.Duration(raw.ReadHeaderTimeout)
+	c.ReadTimeout = time.Duration(raw.ReadTimeout)
+	c.WriteTimeout = time.Duration(raw.WriteTimeout)
+	c.IdleTimeout = time.Duration(raw.IdleTimeout)
+	return nil
+}
+
+// jsonDuration - duration in JSON: seconds (15) or duration string ("500ms"), same as route option timeout
+type jsonDuration time.Duration
+
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case float64:
+		*d = jsonDuration(v * float64(time.Second))
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*d = jsonDuration(parsed)
+	default:
+		return fmt.Errorf("duration must be seconds or string, got %s", data)
+	}
+	return nil
+}
+
+func (c HTTPConfig) withDefaults() HTTPConfig {
+	if c.ContentType == "" {
+		c.ContentType = ContentTypeJSON
+	}
+	if c.ReadHeaderTimeout == 0 {
+		c.ReadHeaderTimeout = defaultReadHeaderTimeout
+	}
+	if c.ReadTimeout == 0 {
+		c.ReadTimeout = defaultReadTimeout
+	}
+	if c.WriteTimeout == 0 {
+		c.WriteTimeout = defaultWriteTimeout
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = defaultIdleTimeout
+	}
+	if c.MaxBodySize == 0 {
+		c.MaxBodySize = defaultMaxBodySize
+	}
+	return c
 }
 
 /*
@@ -41,14 +158,96 @@ HTTPServer - duh!
 type HTTPServer struct {
 	Config HTTPConfig
 	Router *Router
+
+	mu      sync.Mutex
+	server  *http.Server
+	stopped chan struct{}
+	once    sync.Once
 }
 
 /*
-Start - stopping server (duuh!)
+Start - starting server (duuh!). Blocks until server is stopped.
+After Shutdown returns nil when all requests are finished
 */
-func (srv *HTTPServer) Start() {
-	log.Println("Starting server: ", "http://"+srv.getHost())
-	log.Fatal(http.ListenAndServe(srv.getHost(), srv.Router.GetRouter()))
+func (srv *HTTPServer) Start() error {
+	server, stopped := srv.getServer()
+	listener, err := srv.listen()
+	if err != nil {
+		return err
+	}
+	log.Println("Starting server: ", srv.getURL())
+	for _, rt := range srv.Router.timeoutsOver(srv.Config.WriteTimeout) {
+		log.Printf("Timeout of %s is longer than WriteTimeout %v: response is cut off by server", rt, srv.Config.WriteTimeout)
+	}
+	if srv.isTLS() {
+		err = server.ServeTLS(listener, srv.Config.TLSCert, srv.Config.TLSKey)
+	} else {
+		err = server.Serve(listener)
+	}
+	if err == http.ErrServerClosed {
+		// Waiting for in-flight requests to be finished by Shutdown
+		<-stopped
+		return nil
+	}
+	return err
+}
+
+/*
+Shutdown - stopping server gracefully: server stops accepting connections
+and waits for in-flight requests until ctx is done
+*/
+func (srv *HTTPServer) Shutdown(ctx context.Context) error {
+	server, stopped := srv.getServer()
+	defer srv.once.Do(func() {
+		close(stopped)
+	})
+	log.Println("Stopping server: ", srv.getURL())
+	return server.Shutdown(ctx)
+}
+
+func (srv *HTTPServer) getServer() (*http.Server, chan struct{}) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.server == nil {
+		conf := srv.Config
+		srv.server = &http.Server{
+			Addr:              srv.getHost(),
+			Handler:           srv.Router.GetRouter(),
+			ReadHeaderTimeout: conf.ReadHeaderTimeout,
+			ReadTimeout:       conf.ReadTimeout,
+			WriteTimeout:      conf.WriteTimeout,
+			IdleTimeout:       conf.IdleTimeout,
+		}
+		srv.stopped = make(chan struct{})
+	}
+	return srv.server, srv.stopped
+}
+
+func (srv *HTTPServer) listen() (net.Listener, error) {
+	if srv.Config.Socket == "" {
+		return net.Listen("tcp", srv.getHost())
+	}
+	// Removing socket file left by previous run
+	if fi, err := os.Stat(srv.Config.Socket); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err = os.Remove(srv.Config.Socket); err != nil {
+			return nil, err
+		}
+	}
+	return net.Listen("unix", srv.Config.Socket)
+}
+
+func (srv *HTTPServer) isTLS() bool {
+	return srv.Config.TLSCert != "" && srv.Config.TLSKey != ""
+}
+
+func (srv *HTTPServer) getURL() string {
+	if srv.Config.Socket != "" {
+		return "unix:" + srv.Config.Socket
+	}
+	if srv.isTLS() {
+		return "https://" + srv.getHost()
+	}
+	return "http://" + srv.getHost()
 }
 
 func (srv *HTTPServer) getHost() string {
@@ -0,0 +1,276 @@
+package vodka
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// responseError - error of default response envelope
+func responseError(t *testing.T, w *httptest.ResponseRecorder) Error {
+	t.Helper()
+	var res struct {
+		Error Error `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("response is not JSON: %v: %s", err, w.Body.String())
+	}
+	return res.Error
+}
+
+func TestHTTPConfigDefaults(t *testing.T) {
+	conf := HTTPConfig{}.withDefaults()
+	want := HTTPConfig{
+		ContentType:       ContentTypeJSON,
+		ReadHeaderTimeout: defaultReadHeaderTimeout,
+		ReadTimeout:       defaultReadTimeout,
+		WriteTimeout:      defaultWriteTimeout,
+		IdleTimeout:       defaultIdleTimeout,
+		MaxBodySize:       defaultMaxBodySize,
+	}
+	if conf != want {
+		t.Errorf("got %+v, want %+v", conf, want)
+	}
+
+	conf = HTTPConfig{ReadTimeout: 500 * time.Millisecond, MaxBodySize: -1}.withDefaults()
+	if conf.ReadTimeout != 500*time.Millisecond || conf.MaxBodySize != -1 {
+		t.Errorf("values that are set are replaced: %+v", conf)
+	}
+}
+
+func TestServerTimeouts(t *testing.T) {
+	app := New()
+	app.Server(HTTPConfig{
+		ReadHeaderTimeout: 500 * time.Millisecond,
+		ReadTimeout:       2 * time.Second,
+		WriteTimeout:      3 * time.Second,
+		IdleTimeout:       4 * time.Second,
+	})
+	server, _ := app.HTTPServer.getServer()
+	if server.ReadHeaderTimeout != 500*time.Millisecond || server.ReadTimeout != 2*time.Second ||
+		server.WriteTimeout != 3*time.Second || server.IdleTimeout != 4*time.Second {
+		t.Errorf("unexpected timeouts: %v %v %v %v", server.ReadHeaderTimeout, server.ReadTimeout, server.WriteTimeout, server.IdleTimeout)
+	}
+}
+
+func TestHTTPConfigJSON(t *testing.T) {
+	var conf HTTPConfig
+	data := `{"port":8080,"readHeaderTimeout":"500ms","readTimeout":15,"writeTimeout":0.25,"maxBodySize":1024}`
+	if err := json.Unmarshal([]byte(data), &conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := HTTPConfig{
+		Port:              8080,
+		ReadHeaderTimeout: 500 * time.Millisecond,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      250 * time.Millisecond,
+		MaxBodySize:       1024,
+	}
+	if conf != want {
+		t.Errorf("got %+v, want %+v", conf, want)
+	}
+
+	for _, data := range []string{`{"readTimeout":"soon"}`, `{"idleTimeout":true}`} {
+		if err := json.Unmarshal([]byte(data), &conf); err == nil {
+			t.Errorf("%s: expected error", data)
+		}
+	}
+}
+
+func TestRouteTimeoutsOverWriteTimeout(t *testing.T) {
+	app := newTestApp()
+	handler := func(ctx *Context) (interface{}, error) { return nil, nil }
+	app.Router.Timeout(time.Minute).GET("/reports", handler)
+	app.Router.Timeout(time.Second).GET("/users", handler)
+	app.Router.GET("/orders", handler)
+	loadTestRules(t, app, `{"/orders": {"get": {"options": {"timeout": "45s"}}}}`)
+
+	got := app.Router.timeoutsOver(30 * time.Second)
+	want := []string{"GET /reports (1m0s)", "GET /orders (45s)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := app.Router.timeoutsOver(0); got != nil {
+		t.Errorf("routes are reported without write timeout: %v", got)
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		max    int64
+		body   string
+		status int
+	}{
+		{"body under limit", 16, `{"name":"x"}`, http.StatusOK},
+		{"body over limit", 16, `{"name":"` + strings.Repeat("x", 32) + `"}`, ErrorRequestTooLargeCode},
+		{"unlimited", -1, `{"name":"` + strings.Repeat("x", 32) + `"}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.Server(HTTPConfig{MaxBodySize: tt.max})
+			app.Router.POST("/items", func(ctx *Context) (interface{}, error) {
+				return "ok", nil
+			})
+			w := serve(app, httptest.NewRequest("POST", "/items", strings.NewReader(tt.body)))
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status == ErrorRequestTooLargeCode {
+				if e := responseError(t, w); e.Code != ErrRequestTooLarge.Code {
+					t.Errorf("expected code %s, got %+v", ErrRequestTooLarge.Code, e)
+				}
+			}
+		})
+	}
+}
+
+func TestBodyLimitOfRouterWithoutServer(t *testing.T) {
+	app := New()
+	app.Router.POST("/items", func(ctx *Context) (interface{}, error) {
+		return "ok", nil
+	})
+	server := httptest.NewServer(app.Router.GetRouter())
+	defer server.Close()
+
+	body := strings.NewReader(`{"name":"` + strings.Repeat("x", defaultMaxBodySize) + `"}`)
+	res, err := http.Post(server.URL+"/items", ContentTypeJSON, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != ErrorRequestTooLargeCode {
+		t.Errorf("expected default limit of router, got %d", res.StatusCode)
+	}
+
+	app.Router.SetMaxBodySize(8)
+	form := strings.NewReader("name=" + strings.Repeat("x", 16))
+	res, err = http.Post(server.URL+"/items", "application/x-www-form-urlencoded", form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != ErrorRequestTooLargeCode {
+		t.Errorf("expected limit of form body, got %d", res.StatusCode)
+	}
+}
+
+// writeTestCert - self-signed certificate for localhost and its key in PEM files of dir
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestServerTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, cert := writeTestCert(t, dir)
+	socket := filepath.Join(dir, "vodka.sock")
+	app := New()
+	app.Server(HTTPConfig{Socket: socket, TLSCert: certFile, TLSKey: keyFile})
+	app.Router.GET("/ping", func(ctx *Context) (interface{}, error) {
+		return ctx.Request.TLS != nil, nil
+	})
+	startErr := make(chan error, 1)
+	go func() { startErr <- app.HTTPServer.Start() }()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: roots},
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			for {
+				conn, err := d.DialContext(ctx, "unix", socket)
+				if err == nil || ctx.Err() != nil {
+					return conn, err
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		},
+	}}
+	res, err := client.Get("https://localhost/ping")
+	if err != nil {
+		t.Fatalf("HTTPS request failed: %v", err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(body), `"data":true`) {
+		t.Errorf("unexpected response %d: %s", res.StatusCode, body)
+	}
+	if res.TLS == nil || len(res.TLS.PeerCertificates) == 0 || !res.TLS.PeerCertificates[0].Equal(cert) {
+		t.Error("response is not served with configured certificate")
+	}
+
+	if err := app.HTTPServer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if err := <-startErr; err != nil {
+		t.Errorf("start: %v", err)
+	}
+}
+
+func TestServerURL(t *testing.T) {
+	tests := []struct {
+		conf HTTPConfig
+		url  string
+	}{
+		{HTTPConfig{Host: "localhost", Port: 8080}, "http://localhost:8080"},
+		{HTTPConfig{Host: "localhost", Port: 8443, TLSCert: "cert.pem", TLSKey: "key.pem"}, "https://localhost:8443"},
+		{HTTPConfig{Host: "localhost", Port: 8443, TLSCert: "cert.pem"}, "http://localhost:8443"},
+		{HTTPConfig{Socket: "/tmp/vodka.sock"}, "unix:/tmp/vodka.sock"},
+	}
+	for _, tt := range tests {
+		srv := &HTTPServer{Config: tt.conf}
+		if url := srv.getURL(); url != tt.url {
+			t.Errorf("%+v: got %s, want %s", tt.conf, url, tt.url)
+		}
+	}
+}
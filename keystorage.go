@@ -0,0 +1,370 @@
+package vodka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Errors of KeyStorage getters. KeyError wraps them, so they can be checked with errors.Is
+var (
+	ErrKeyNotFound = errors.New("key is not found")
+	ErrKeyType     = errors.New("value can't be converted")
+)
+
+/*
+KeyError - error of getting value from KeyStorage
+*/
+type KeyError struct {
+	Key      string
+	Expected string
+	Value    interface{}
+	Err      error
+}
+
+func (e *KeyError) Error() string {
+	if e.Err == ErrKeyNotFound {
+		return fmt.Sprintf("%s: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("%s (%v): %v to %s", e.Key, e.Value, e.Err, e.Expected)
+}
+
+// Unwrap - ErrKeyNotFound or ErrKeyType
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// Has - checking that key is set
+func (q *KeyStorage) Has(key string) bool {
+	_, ok := q.keys[key]
+	return ok
+}
+
+// Lookup - getting value by key and flag that key is set
+func (q *KeyStorage) Lookup(key string) (interface{}, bool) {
+	v, ok := q.keys[key]
+	return v, ok
+}
+
+// Keys - sorted keys
+func (q *KeyStorage) Keys() []string {
+	keys := make([]string, 0, len(q.keys))
+	for key := range q.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Copy - copy of values. Unlike Map changes of copy don't affect storage
+func (q *KeyStorage) Copy() map[string]interface{} {
+	m := make(map[string]interface{}, len(q.keys))
+	for key, v := range q.keys {
+		m[key] = v
+	}
+	return m
+}
+
+// MarshalJSON - KeyStorage is encoded as JSON object
+func (q KeyStorage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.Copy())
+}
+
+// UnmarshalJSON - decoding JSON object to KeyStorage
+func (q *KeyStorage) UnmarshalJSON(data []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	q.keys = m
+	return nil
+}
+
+// ToInt - getting value converted to int: 42, 42.0, "42"
+func (q *KeyStorage) ToInt(key string) (int, error) {
+	v, err := q.ToInt64(key)
+	if err != nil {
+		err.(*KeyError).Expected = "int"
+		return 0, err
+	}
+	if int64(int(v)) != v {
+		return 0, q.typeError(key, "int")
+	}
+	return int(v), nil
+}
+
+// ToInt64 - getting value converted to int64
+func (q *KeyStorage) ToInt64(key string) (int64, error) {
+	value, ok := q.keys[key]
+	if !ok || value == nil {
+		return 0, &KeyError{Key: key, Expected: "int64", Err: ErrKeyNotFound}
+	}
+	v, ok := convertInt64(value)
+	if !ok {
+		return 0, q.typeError(key, "int64")
+	}
+	return v, nil
+}
+
+// ToFloat64 - getting value converted to float64
+func (q *KeyStorage) ToFloat64(key string) (float64, error) {
+	value, ok := q.keys[key]
+	if !ok || value == nil {
+		return 0, &KeyError{Key: key, Expected: "float64", Err: ErrKeyNotFound}
+	}
+	v, ok := convertFloat64(value)
+	if !ok {
+		return 0, q.typeError(key, "float64")
+	}
+	return v, nil
+}
+
+// ToString - getting value converted to string. Numbers and bools are formatted
+func (q *KeyStorage) ToString(key string) (string, error) {
+	value, ok := q.keys[key]
+	if !ok || value == nil {
+		return "", &KeyError{Key: key, Expected: "string", Err: ErrKeyNotFound}
+	}
+	v, ok := convertString(value)
+	if !ok {
+		return "", q.typeError(key, "string")
+	}
+	return v, nil
+}
+
+// ToBool - getting value converted to bool: true, "true", "1", 1
+func (q *KeyStorage) ToBool(key string) (bool, error) {
+	value, ok := q.keys[key]
+	if !ok || value == nil {
+		return false, &KeyError{Key: key, Expected: "bool", Err: ErrKeyNotFound}
+	}
+	v, ok := convertBool(value)
+	if !ok {
+		return false, q.typeError(key, "bool")
+	}
+	return v, nil
+}
+
+// ToArrayInt - getting value converted to []int64. Strings are split by comma
+func (q *KeyStorage) ToArrayInt(key string) ([]int64, error) {
+	els, err := q.elements(key, "[]int64")
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, len(els))
+	for i, el := range els {
+		v, ok := convertInt64(el)
+		if !ok {
+			return nil, q.typeError(key, "[]int64")
+		}
+		res[i] = v
+	}
+	return res, nil
+}
+
+// ToArrayFloat - getting value converted to []float64. Strings are split by comma
+func (q *KeyStorage) ToArrayFloat(key string) ([]float64, error) {
+	els, err := q.elements(key, "[]float64")
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, len(els))
+	for i, el := range els {
+		v, ok := convertFloat64(el)
+		if !ok {
+			return nil, q.typeError(key, "[]float64")
+		}
+		res[i] = v
+	}
+	return res, nil
+}
+
+// ToArrayString - getting value converted to []string. Strings are split by comma
+func (q *KeyStorage) ToArrayString(key string) ([]string, error) {
+	els, err := q.elements(key, "[]string")
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, len(els))
+	for i, el := range els {
+		v, ok := convertString(el)
+		if !ok {
+			return nil, q.typeError(key, "[]string")
+		}
+		res[i] = v
+	}
+	return res, nil
+}
+
+// GetIntOr - getting value converted to int or def if key is not set or can't be converted
+func (q *KeyStorage) GetIntOr(key string, def int) int {
+	if v, err := q.ToInt(key); err == nil {
+		return v
+	}
+	return def
+}
+
+// GetInt64Or - getting value converted to int64 or def
+func (q *KeyStorage) GetInt64Or(key string, def int64) int64 {
+	if v, err := q.ToInt64(key); err == nil {
+		return v
+	}
+	return def
+}
+
+// GetFloat64Or - getting value converted to float64 or def
+func (q *KeyStorage) GetFloat64Or(key string, def float64) float64 {
+	if v, err := q.ToFloat64(key); err == nil {
+		return v
+	}
+	return def
+}
+
+// GetStringOr - getting value converted to string or def
+func (q *KeyStorage) GetStringOr(key string, def string) string {
+	if v, err := q.ToString(key); err == nil {
+		return v
+	}
+	return def
+}
+
+// GetBoolOr - getting value converted to bool or def
+func (q *KeyStorage) GetBoolOr(key string, def bool) bool {
+	if v, err := q.ToBool(key); err == nil {
+		return v
+	}
+	return def
+}
+
+func (q *KeyStorage) typeError(key, expected string) error {
+	return &KeyError{Key: key, Expected: expected, Value: q.keys[key], Err: ErrKeyType}
+}
+
+// elements - elements of slice value. String is split by comma
+func (q *KeyStorage) elements(key, expected string) ([]interface{}, error) {
+	value, ok := q.keys[key]
+	if !ok || value == nil {
+		return nil, &KeyError{Key: key, Expected: expected, Err: ErrKeyNotFound}
+	}
+	if s, ok := value.(string); ok {
+		var els []interface{}
+		for _, el := range strings.Split(s, ",") {
+			els = append(els, el)
+		}
+		return els, nil
+	}
+	switch value.(type) {
+	case []interface{}, []int64, []float64, []string:
+		return elements(value), nil
+	case []int:
+		var els []interface{}
+		for _, el := range value.([]int) {
+			els = append(els, el)
+		}
+		return els, nil
+	}
+	return nil, q.typeError(key, expected)
+}
+
+func convertInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case float32:
+		return convertInt64(float64(v))
+	case float64:
+		// Only whole numbers: 42.0 but not 42.5. float64(math.MaxInt64) rounds up to 2^63, so it's excluded
+		if v != math.Trunc(v) || v >= 1<<63 || v < -1<<63 {
+			return 0, false
+		}
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func convertFloat64(value interface{}) (float64, bool) {
+	if n, ok := toFloat(value); ok {
+		return n, true
+	}
+	switch v := value.(type) {
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	if n, ok := convertInt64(value); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func convertString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case json.Number:
+		return v.String(), true
+	}
+	if n, ok := convertInt64(value); ok {
+		return strconv.FormatInt(n, 10), true
+	}
+	return "", false
+}
+
+func convertBool(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		return b, err == nil
+	}
+	if n, ok := convertFloat64(value); ok {
+		return n != 0, true
+	}
+	return false, false
+}
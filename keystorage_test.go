@@ -0,0 +1,161 @@
+package vodka
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func newTestStorage() KeyStorage {
+	var ks KeyStorage
+	ks.Set("int", 42)
+	ks.Set("float", 42.0)
+	ks.Set("fraction", 42.5)
+	ks.Set("number", json.Number("7"))
+	ks.Set("string", " 12 ")
+	ks.Set("word", "abc")
+	ks.Set("bool", "true")
+	ks.Set("uint", uint64(1<<63))
+	ks.Set("csv", "1,2,3")
+	ks.Set("list", []interface{}{1.0, "2"})
+	ks.Set("nil", nil)
+	return ks
+}
+
+func TestKeyStorageTo(t *testing.T) {
+	ks := newTestStorage()
+	if v, err := ks.ToInt("float"); err != nil || v != 42 {
+		t.Errorf("ToInt(float) = %v, %v", v, err)
+	}
+	if v, err := ks.ToInt64("number"); err != nil || v != 7 {
+		t.Errorf("ToInt64(number) = %v, %v", v, err)
+	}
+	if v, err := ks.ToInt64("string"); err != nil || v != 12 {
+		t.Errorf("ToInt64(string) = %v, %v", v, err)
+	}
+	if v, err := ks.ToFloat64("int"); err != nil || v != 42 {
+		t.Errorf("ToFloat64(int) = %v, %v", v, err)
+	}
+	if v, err := ks.ToString("fraction"); err != nil || v != "42.5" {
+		t.Errorf("ToString(fraction) = %v, %v", v, err)
+	}
+	if v, err := ks.ToBool("bool"); err != nil || !v {
+		t.Errorf("ToBool(bool) = %v, %v", v, err)
+	}
+	if v, err := ks.ToArrayInt("csv"); err != nil || !reflect.DeepEqual(v, []int64{1, 2, 3}) {
+		t.Errorf("ToArrayInt(csv) = %v, %v", v, err)
+	}
+	if v, err := ks.ToArrayString("list"); err != nil || !reflect.DeepEqual(v, []string{"1", "2"}) {
+		t.Errorf("ToArrayString(list) = %v, %v", v, err)
+	}
+	if v, err := ks.ToArrayFloat("list"); err != nil || !reflect.DeepEqual(v, []float64{1, 2}) {
+		t.Errorf("ToArrayFloat(list) = %v, %v", v, err)
+	}
+}
+
+func TestConvertInt64FloatBounds(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want int64
+		ok   bool
+	}{
+		{math.Ldexp(1, 63), 0, false},
+		{math.Nextafter(math.Ldexp(1, 63), 0), 1<<63 - 1024, true},
+		{-math.Ldexp(1, 63), math.MinInt64, true},
+		{math.Nextafter(-math.Ldexp(1, 63), math.Inf(-1)), 0, false},
+		{math.Inf(1), 0, false},
+		{math.NaN(), 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := convertInt64(tt.v); got != tt.want || ok != tt.ok {
+			t.Errorf("convertInt64(%v) = %v, %v, want %v, %v", tt.v, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestKeyStorageToErrors(t *testing.T) {
+	ks := newTestStorage()
+	tests := []struct {
+		name string
+		get  func() error
+		err  error
+	}{
+		{"absent", func() error { _, err := ks.ToInt("absent"); return err }, ErrKeyNotFound},
+		{"nil", func() error { _, err := ks.ToString("nil"); return err }, ErrKeyNotFound},
+		{"fraction to int", func() error { _, err := ks.ToInt64("fraction"); return err }, ErrKeyType},
+		{"word to float", func() error { _, err := ks.ToFloat64("word"); return err }, ErrKeyType},
+		{"big uint", func() error { _, err := ks.ToInt64("uint"); return err }, ErrKeyType},
+		{"word to bool", func() error { _, err := ks.ToBool("word"); return err }, ErrKeyType},
+		{"word to array", func() error { _, err := ks.ToArrayInt("word"); return err }, ErrKeyType},
+		{"int to array", func() error { _, err := ks.ToArrayString("int"); return err }, ErrKeyType},
+	}
+	for _, tt := range tests {
+		err := tt.get()
+		var keyErr *KeyError
+		if !errors.Is(err, tt.err) || !errors.As(err, &keyErr) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		}
+	}
+
+	_, err := ks.ToInt("word")
+	if err == nil || err.Error() != "word (abc): value can't be converted to int" {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestKeyStorageGetOr(t *testing.T) {
+	ks := newTestStorage()
+	if v := ks.GetIntOr("string", 1); v != 12 {
+		t.Errorf("GetIntOr(string) = %v", v)
+	}
+	if v := ks.GetIntOr("word", 1); v != 1 {
+		t.Errorf("GetIntOr(word) = %v", v)
+	}
+	if v := ks.GetInt64Or("absent", 2); v != 2 {
+		t.Errorf("GetInt64Or(absent) = %v", v)
+	}
+	if v := ks.GetFloat64Or("fraction", 0); v != 42.5 {
+		t.Errorf("GetFloat64Or(fraction) = %v", v)
+	}
+	if v := ks.GetStringOr("int", ""); v != "42" {
+		t.Errorf("GetStringOr(int) = %v", v)
+	}
+	if v := ks.GetBoolOr("word", true); !v {
+		t.Errorf("GetBoolOr(word) = %v", v)
+	}
+}
+
+func TestKeyStorageKeys(t *testing.T) {
+	var ks KeyStorage
+	if ks.Has("a") || len(ks.Keys()) != 0 {
+		t.Error("empty storage has keys")
+	}
+	ks.Set("b", nil)
+	ks.Set("a", 1)
+	if !ks.Has("b") || !reflect.DeepEqual(ks.Keys(), []string{"a", "b"}) {
+		t.Errorf("unexpected keys: %v", ks.Keys())
+	}
+	if v, ok := ks.Lookup("b"); !ok || v != nil {
+		t.Errorf("Lookup(b) = %v, %v", v, ok)
+	}
+	copied := ks.Copy()
+	copied["c"] = 3
+	if ks.Has("c") {
+		t.Error("change of copy affects storage")
+	}
+}
+
+func TestKeyStorageJSON(t *testing.T) {
+	var ks KeyStorage
+	ks.Set("name", "x")
+	b, err := json.Marshal(struct{ Body KeyStorage }{ks})
+	if err != nil || string(b) != `{"Body":{"name":"x"}}` {
+		t.Errorf("unexpected JSON: %s, %v", b, err)
+	}
+	var decoded KeyStorage
+	if err := json.Unmarshal([]byte(`{"age":3}`), &decoded); err != nil || decoded.GetIntOr("age", 0) != 3 {
+		t.Errorf("unexpected storage: %v, %v", decoded.Map(), err)
+	}
+}
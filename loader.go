@@ -0,0 +1,210 @@
+package vodka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+/*
+WatchValidation - reloading validation rules when files are changed.
+Files are checked every interval and new rules replace old ones atomically.
+Invalid rules are logged and old ones are kept. Watcher is stopped on Shutdown
+*/
+func (e *Application) WatchValidation(interval time.Duration) error {
+	source := e.validator.getSource()
+	if source == "" {
+		return errors.New("validation rules are not loaded")
+	}
+	last, err := rulesSignature(source)
+	if err != nil {
+		return err
+	}
+	stop := make(chan struct{})
+	var once sync.Once
+	e.OnShutdown(func(context.Context) error {
+		once.Do(func() { close(stop) })
+		return nil
+	})
+	go e.validator.watch(source, last, interval, stop)
+	return nil
+}
+
+func (v *Validator) watch(source, last string, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		signature, err := rulesSignature(source)
+		if err != nil {
+			log.Println("Validation rules are not checked: ", err)
+			continue
+		}
+		if signature == last {
+			continue
+		}
+		last = signature
+		rules, err := loadRulesPath(source)
+		if err != nil {
+			log.Println("Validation rules are not reloaded: ", err)
+			continue
+		}
+		v.setRules(source, rules)
+		log.Println("Validation rules are reloaded")
+	}
+}
+
+/*
+loadRulesPath - loading rules from file or from all JSON and YAML files of directory.
+Rules of files are merged by path, same path and method in two files is an error
+*/
+func loadRulesPath(path string) (map[string]routeRules, error) {
+	files, err := rulesFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	rules := make(map[string]routeRules)
+	defined := make(map[string]string)
+	for _, file := range files {
+		fileRules, err := loadRulesFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		for _, routePath := range sortedRoutePaths(fileRules) {
+			if rules[routePath] == nil {
+				rules[routePath] = make(routeRules)
+			}
+			for method, mr := range fileRules[routePath] {
+				method = strings.ToLower(method)
+				id := strings.ToUpper(method) + " " + routePath
+				if source, ok := defined[id]; ok {
+					return nil, fmt.Errorf("validation rules of %s are defined in %s and %s", id, source, file)
+				}
+				defined[id] = file
+				if err := compileMethodRules(mr); err != nil {
+					return nil, fmt.Errorf("%s: %s: %v", file, id, err)
+				}
+				if err := checkFieldRules(mr); err != nil {
+					return nil, fmt.Errorf("%s: %s: %v", file, id, err)
+				}
+				rules[routePath][method] = mr
+			}
+		}
+	}
+	return rules, nil
+}
+
+// compileMethodRules - compiling rules of params, query and body
+func compileMethodRules(mr methodRules) error {
+	for _, rules := range []map[string]validation{mr.Params, mr.Query, mr.Body} {
+		if err := compileRules(rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadRulesFile - rules of JSON or YAML file
+func loadRulesFile(fileName string) (rules map[string]routeRules, err error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if isYAMLFile(fileName) {
+		// YAML is converted to JSON to get the same types of values as in JSON files
+		var raw interface{}
+		if err = yaml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(jsonValue(raw)); err != nil {
+			return nil, err
+		}
+	}
+	err = json.Unmarshal(data, &rules)
+	return
+}
+
+// rulesFiles - file itself or JSON and YAML files of directory and subdirectories in lexical order
+func rulesFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	var files []string
+	err = filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && isRulesFile(name) {
+			files = append(files, name)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// rulesSignature - names, sizes and modification times of rule files to detect changes
+func rulesSignature(path string) (string, error) {
+	files, err := rulesFiles(path)
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d:%d", file, info.Size(), info.ModTime().UnixNano()))
+	}
+	return strings.Join(parts, "|"), nil
+}
+
+func isRulesFile(name string) bool {
+	return strings.ToLower(filepath.Ext(name)) == ".json" || isYAMLFile(name)
+}
+
+// jsonValue - converting YAML maps with interface{} keys to JSON objects
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, el := range v {
+			m[fmt.Sprint(key)] = jsonValue(el)
+		}
+		return m
+	case []interface{}:
+		for i, el := range v {
+			v[i] = jsonValue(el)
+		}
+		return v
+	}
+	return value
+}
+
+func sortedRoutePaths(rules map[string]routeRules) []string {
+	paths := make([]string, 0, len(rules))
+	for path := range rules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,138 @@
+package vodka
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeRules - writing rules file to dir
+func writeRules(t *testing.T, dir, name, rules string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRulesFromYAML(t *testing.T) {
+	path := writeRules(t, t.TempDir(), "validation.yaml", `
+/users/:id:
+  put:
+    params:
+      id: {type: int64, required: true}
+    body:
+      age: {type: int, min: 1}
+      tags: {type: array, items: {type: string, enum: [a, b]}}
+    options:
+      timeout: 5
+`)
+	rules, err := loadRulesPath(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	put := rules["/users/:id"]["put"]
+	if put.Params["id"].InputType != "int64" || !put.Params["id"].Required {
+		t.Errorf("unexpected params: %+v", put.Params)
+	}
+	if age := put.Body["age"]; age.Min == nil || *age.Min != 1 {
+		t.Errorf("unexpected age rule: %+v", age)
+	}
+	if tags := put.Body["tags"]; tags.Items == nil || len(tags.Items.Enum) != 2 {
+		t.Errorf("unexpected tags rule: %+v", tags)
+	}
+	// Numbers have the same types as in JSON
+	if timeout, ok := put.Options["timeout"].(float64); !ok || timeout != 5 {
+		t.Errorf("unexpected options: %#v", put.Options)
+	}
+}
+
+func TestLoadRulesFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeRules(t, dir, "users.json", `{"/users": {"get": {"query": {"name": {"type": "string"}}}}}`)
+	writeRules(t, dir, "users/admin.yml", "/users:\n  post:\n    body:\n      name: {type: string}\n")
+	writeRules(t, dir, "README.md", "not rules")
+
+	rules, err := loadRulesPath(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	users := rules["/users"]
+	if len(users) != 2 || users["get"].Query == nil || users["post"].Body == nil {
+		t.Errorf("rules of files are not merged: %+v", users)
+	}
+}
+
+func TestLoadRulesErrors(t *testing.T) {
+	dir := t.TempDir()
+	first := writeRules(t, dir, "a.json", `{"/users": {"GET": {}}}`)
+	second := writeRules(t, dir, "b.yaml", "/users:\n  get: {}\n")
+	_, err := loadRulesPath(dir)
+	if err == nil || !strings.Contains(err.Error(), "GET /users are defined in "+first+" and "+second) {
+		t.Errorf("expected error of duplicate, got %v", err)
+	}
+
+	broken := writeRules(t, t.TempDir(), "broken.json", `{"/users": `)
+	if _, err = loadRulesPath(broken); err == nil || !strings.HasPrefix(err.Error(), broken+": ") {
+		t.Errorf("expected error with file name, got %v", err)
+	}
+	if _, err = loadRulesPath(filepath.Join(dir, "absent.json")); err == nil {
+		t.Error("expected error for absent file")
+	}
+}
+
+func TestWatchValidationReloadsRules(t *testing.T) {
+	app := newTestApp()
+	if err := app.WatchValidation(time.Millisecond); err == nil {
+		t.Error("expected error when rules are not loaded")
+	}
+	dir := t.TempDir()
+	writeRules(t, dir, "users.json", `{"/users": {"post": {"body": {"name": {"type": "string", "required": true}}}}}`)
+	if err := app.Validation(dir); err != nil {
+		t.Fatal(err)
+	}
+	app.Router.POST("/users", func(ctx *Context) (interface{}, error) { return nil, nil })
+	if err := app.WatchValidation(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	defer app.applyShutdownHooks(context.Background())
+
+	post := func() int {
+		return serve(app, httptest.NewRequest("POST", "/users", strings.NewReader(`{}`))).Code
+	}
+	if code := post(); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 by loaded rules, got %d", code)
+	}
+
+	writeRules(t, dir, "users.json", `{"/users": {"post": {"body": {"name": {"type": "string", "required": false}}}}}`)
+	waitFor(t, func() bool { return post() == http.StatusOK })
+
+	// Invalid rules are not applied
+	writeRules(t, dir, "users.json", `{"/users": {"post": {"body": {"name": {"type": "string", "pattern": "("}}}}}`)
+	time.Sleep(50 * time.Millisecond)
+	if code := post(); code != http.StatusOK {
+		t.Errorf("invalid rules are applied: %d", code)
+	}
+}
+
+// waitFor - waiting until condition is true
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition is not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,11 +1,18 @@
 package vodka
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
+const defaultShutdownTimeout = 30 * time.Second
+
 var isDebug bool
 
 // Application - main app struct
@@ -16,7 +23,13 @@ type Application struct {
 	middlewares []Middleware
 	hooks       []Hook
 	decorator   Decorator
+	onStart     []func() error
+	onShutdown  []func(context.Context) error
+	panicHooks  []PanicHook
+	container   Container
 	Debug       bool
+	// ShutdownTimeout - time to wait for in-flight requests on SIGINT/SIGTERM
+	ShutdownTimeout time.Duration
 }
 
 // Middleware - middleware service
@@ -39,25 +52,92 @@ New - Application constructor
 */
 func New() *Application {
 	app := Application{
-		Router:    NewRouter(),
-		validator: Validator{},
+		Router:          NewRouter(),
+		validator:       Validator{},
+		ShutdownTimeout: defaultShutdownTimeout,
 	}
 	app.Router.dispatch = app.dispatch
+	app.Router.sendError = app.sendError
 	if os.Getenv("DEBUG") == "true" {
 		isDebug = true
 	}
 	return &app
 }
 
-// Run - starting server to run
-func (e *Application) Run() {
+/*
+Run - running OnStart hooks and starting server.
+Blocks until server is stopped by SIGINT/SIGTERM, Shutdown or error.
+On signal in-flight requests are finished and OnShutdown hooks are called before return
+*/
+func (e *Application) Run() error {
 	log.Println("Running")
-	e.HTTPServer.Start()
+	for _, hook := range e.onStart {
+		if err := hook(); err != nil {
+			// Hooks that are started before may hold resources
+			e.applyShutdownHooks(context.Background())
+			return err
+		}
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan struct{})
+	shutdown := make(chan error, 1)
+	go func() {
+		select {
+		case sig := <-signals:
+			log.Println("Received signal: ", sig)
+			ctx, cancel := context.WithTimeout(context.Background(), e.ShutdownTimeout)
+			defer cancel()
+			shutdown <- e.Shutdown(ctx)
+		case <-done:
+			shutdown <- nil
+		}
+	}()
+
+	err := e.HTTPServer.Start()
+	close(done)
+	if shutdownErr := <-shutdown; err == nil {
+		err = shutdownErr
+	} else {
+		// Server failed, so shutdown hooks have to release resources
+		e.applyShutdownHooks(context.Background())
+	}
+	return err
 }
 
 // Start - same as Run
-func (e *Application) Start() {
-	e.Run()
+func (e *Application) Start() error {
+	return e.Run()
+}
+
+/*
+Shutdown - stopping server gracefully and calling OnShutdown hooks.
+Waits for in-flight requests until ctx is done
+*/
+func (e *Application) Shutdown(ctx context.Context) error {
+	err := e.HTTPServer.Shutdown(ctx)
+	if hookErr := e.applyShutdownHooks(ctx); err == nil {
+		err = hookErr
+	}
+	return err
+}
+
+/*
+OnStart - setting hook that is called before server is started.
+Error stops application from starting, OnShutdown hooks are called and dependencies are closed
+*/
+func (e *Application) OnStart(hook func() error) {
+	e.onStart = append(e.onStart, hook)
+}
+
+/*
+OnShutdown - setting hook that is called after server is stopped.
+Dependencies registered with Provide are closed after hooks
+*/
+func (e *Application) OnShutdown(hook func(context.Context) error) {
+	e.onShutdown = append(e.onShutdown, hook)
 }
 
 /*
@@ -78,20 +158,19 @@ func (e *Application) Hook(hook Hook) {
 Server - constructor of HTTP server!
 */
 func (e *Application) Server(conf HTTPConfig) {
-	if conf.ContentType == "" {
-		conf.ContentType = ContentTypeJSON
-	}
 	e.HTTPServer = &HTTPServer{
-		Config: conf,
+		Config: conf.withDefaults(),
 		Router: e.Router,
 	}
+	e.Router.SetMaxBodySize(e.HTTPServer.Config.MaxBodySize)
 }
 
 /*
-Validation - setup validation rules
+Validation - setup validation rules from JSON or YAML file, or from directory of files.
+Rules can be loaded before or after routes are registered
 */
-func (e *Application) Validation(fileName string) error {
-	err := e.validator.loadRules(fileName)
+func (e *Application) Validation(path string) error {
+	err := e.validator.loadRules(path)
 	if err != nil {
 		return err
 	}
@@ -107,12 +186,25 @@ func (e *Application) Decorator(d Decorator) {
 }
 
 func (e *Application) dispatch(ctx *Context) {
+	defer e.recoverPanic(ctx)
+
+	ctx.container = &e.container
+	ctx.types = e.validator.customTypes()
+	if timeout := routeTimeout(ctx); timeout > 0 {
+		c, cancel := context.WithTimeout(ctx.Context(), timeout)
+		defer cancel()
+		ctx.SetContext(c)
+	}
 
 	var err error
+	// Application hooks and middlewares go before route ones
+	ctx.hooks = append(append([]Hook{}, e.hooks...), ctx.hooks...)
+	ctx.middlewares = append(append([]Middleware{}, e.middlewares...), ctx.middlewares...)
+
 	// Validating request
 	err = e.validate(ctx)
 	if err != nil {
-		e.sendResponse(ctx, nil, NewBadRequestError("validation", err.Error()))
+		e.sendResponse(ctx, nil, err)
 		return
 	}
 	// Processing hooks
@@ -122,7 +214,7 @@ func (e *Application) dispatch(ctx *Context) {
 	}
 
 	// Processing middlewares
-	if len(e.middlewares) > 0 {
+	if len(ctx.middlewares) > 0 {
 		e.applyMiddlewares(ctx)
 	} else {
 		e.applyHandler(ctx)
@@ -134,10 +226,10 @@ func (e *Application) applyMiddlewares(ctx *Context) {
 }
 
 func (e *Application) applyMiddleware(ctx *Context) {
-	if ctx.iterator < len(e.middlewares) {
-		handler := e.middlewares[ctx.iterator]
+	if ctx.iterator < len(ctx.middlewares) {
+		handler := ctx.middlewares[ctx.iterator]
 		ctx.iterator++
-		if ctx.iterator < len(e.middlewares) {
+		if ctx.iterator < len(ctx.middlewares) {
 			ctx.Next = e.applyMiddleware
 		} else {
 			ctx.Next = e.applyHandler
@@ -158,8 +250,8 @@ func (e *Application) applyHandler(ctx *Context) {
 
 func (e *Application) applyHooks(ctx *Context) (*Context, error) {
 	var err error
-	if len(e.hooks) > 0 {
-		for _, hook := range e.hooks {
+	if len(ctx.hooks) > 0 {
+		for _, hook := range ctx.hooks {
 			if ctx, err = hook(ctx); err != nil {
 				e.sendResponse(ctx, nil, err)
 				return ctx, err
@@ -169,6 +261,22 @@ func (e *Application) applyHooks(ctx *Context) (*Context, error) {
 	return ctx, err
 }
 
+func (e *Application) applyShutdownHooks(ctx context.Context) (err error) {
+	for _, hook := range e.onShutdown {
+		if hookErr := hook(ctx); hookErr != nil {
+			log.Println("Shutdown hook error: ", hookErr)
+			if err == nil {
+				err = hookErr
+			}
+		}
+	}
+	// Dependencies are closed after hooks, so hooks still can use them
+	if closeErr := e.container.Close(); err == nil {
+		err = closeErr
+	}
+	return
+}
+
 func (e *Application) decorate(data interface{}, err error) []byte {
 	if e.decorator != nil {
 		return e.decorator(data, err)
@@ -192,8 +300,26 @@ func (e *Application) decorate(data interface{}, err error) []byte {
 	return b
 }
 
+func (e *Application) sendError(ctx *Context, err error) {
+	e.sendResponse(ctx, nil, err)
+}
+
+// contentType - content type of responses. Router can be mounted on own server without Application.Server
+func (e *Application) contentType() string {
+	if e.HTTPServer == nil || e.HTTPServer.Config.ContentType == "" {
+		return ContentTypeJSON
+	}
+	return e.HTTPServer.Config.ContentType
+}
+
 func (e *Application) sendResponse(ctx *Context, data interface{}, err error) {
-	ctx.Writer.Header().Set("Content-Type", e.HTTPServer.Config.ContentType)
+	// Query that is cancelled by route timeout is 504
+	if errors.Is(err, context.DeadlineExceeded) && !errors.As(err, new(Error)) {
+		err = NewGatewayTimeoutError(ErrTimeout.Message, err.Error())
+	}
+	// Wrapped Error, sql.ErrNoRows and mapped errors get their status
+	err = toError(err)
+	ctx.Writer.Header().Set("Content-Type", e.contentType())
 	if e, ok := err.(Error); ok {
 		ctx.Writer.WriteHeader(e.httpCode)
 	} else {
@@ -0,0 +1,139 @@
+package vodka
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestApp - application with default server config
+func newTestApp() *Application {
+	app := New()
+	app.Server(HTTPConfig{})
+	return app
+}
+
+// serve - response of application router to request
+func serve(app *Application, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	app.Router.GetRouter().ServeHTTP(w, req)
+	return w
+}
+
+// testCloser - dependency that records Close calls
+type testCloser struct {
+	name   string
+	closed *[]string
+}
+
+func (c testCloser) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return nil
+}
+
+func TestRunStopsOnStartError(t *testing.T) {
+	app := newTestApp()
+	errStart := errors.New("no database")
+	var calls []string
+	app.Provide(NewKey("db"), testCloser{name: "db", closed: &calls})
+	app.OnStart(func() error {
+		calls = append(calls, "start")
+		return nil
+	})
+	app.OnStart(func() error { return errStart })
+	app.OnStart(func() error {
+		t.Error("hook after failed one is called")
+		return nil
+	})
+	app.OnShutdown(func(context.Context) error {
+		calls = append(calls, "shutdown")
+		return nil
+	})
+
+	if err := app.Run(); err != errStart {
+		t.Fatalf("expected error of hook, got %v", err)
+	}
+	want := []string{"start", "shutdown", "db"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls: got %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls: got %v, want %v", calls, want)
+		}
+	}
+}
+
+func TestShutdownWaitsForInFlightRequests(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "vodka.sock")
+	app := New()
+	app.Server(HTTPConfig{Socket: socket})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	app.Router.GET("/slow", func(ctx *Context) (interface{}, error) {
+		close(started)
+		<-release
+		return "done", nil
+	})
+	shutdownHook := make(chan struct{})
+	app.OnShutdown(func(context.Context) error {
+		close(shutdownHook)
+		return nil
+	})
+
+	runErr := make(chan error, 1)
+	go func() { runErr <- app.Run() }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			for {
+				conn, err := d.DialContext(ctx, "unix", socket)
+				if err == nil || ctx.Err() != nil {
+					return conn, err
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		},
+	}}
+	type result struct {
+		status int
+		err    error
+	}
+	response := make(chan result, 1)
+	go func() {
+		res, err := client.Get("http://vodka/slow")
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		res.Body.Close()
+		response <- result{status: res.StatusCode}
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- app.Shutdown(context.Background()) }()
+	select {
+	case <-shutdownHook:
+		t.Fatal("shutdown hook is called before in-flight request is finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	if res := <-response; res.err != nil || res.status != http.StatusOK {
+		t.Errorf("in-flight request is not finished: %+v", res)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+	if err := <-runErr; err != nil {
+		t.Errorf("unexpected run error: %v", err)
+	}
+	<-shutdownHook
+}
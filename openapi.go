@@ -0,0 +1,477 @@
+package vodka
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const openAPIVersion = "3.0.3"
+
+// Schema - JSON schema of OpenAPI document
+type Schema map[string]interface{}
+
+// OpenAPIInfo - info section of OpenAPI document
+type OpenAPIInfo struct {
+	Title       string `json:"title" yaml:"title"`
+	Version     string `json:"version" yaml:"version"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// OpenAPIParameter - path or query parameter of operation
+type OpenAPIParameter struct {
+	Name     string `json:"name" yaml:"name"`
+	In       string `json:"in" yaml:"in"`
+	Required bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema   Schema `json:"schema" yaml:"schema"`
+}
+
+// OpenAPIMedia - content of request body or response
+type OpenAPIMedia struct {
+	Schema Schema `json:"schema" yaml:"schema"`
+}
+
+// OpenAPIBody - request body of operation
+type OpenAPIBody struct {
+	Required bool                    `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]OpenAPIMedia `json:"content" yaml:"content"`
+}
+
+// OpenAPIResponse - response of operation
+type OpenAPIResponse struct {
+	Description string                  `json:"description" yaml:"description"`
+	Content     map[string]OpenAPIMedia `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// OpenAPIOperation - HTTP method of path
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *OpenAPIBody               `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses" yaml:"responses"`
+}
+
+// OpenAPIComponents - reusable schemas of models
+type OpenAPIComponents struct {
+	Schemas map[string]Schema `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+}
+
+/*
+OpenAPI - OpenAPI 3 document generated from routes and validation rules
+*/
+type OpenAPI struct {
+	OpenAPI    string                                  `json:"openapi" yaml:"openapi"`
+	Info       OpenAPIInfo                             `json:"info" yaml:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths" yaml:"paths"`
+	Components OpenAPIComponents                       `json:"components,omitempty" yaml:"components,omitempty"`
+
+	// schemaNames - component names of model types
+	schemaNames map[reflect.Type]string
+}
+
+/*
+OpenAPI - generating OpenAPI 3 document from registered routes.
+Parameters and request body are described by validation rules,
+responses by models of resources (Router.Resource)
+*/
+func (r *Router) OpenAPI(info OpenAPIInfo) *OpenAPI {
+	doc := &OpenAPI{
+		OpenAPI: openAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+		Components: OpenAPIComponents{
+			Schemas: make(map[string]Schema),
+		},
+		schemaNames: make(map[reflect.Type]string),
+	}
+	root := r.root()
+	for _, rt := range root.routes {
+		rt.rules = root.routeRules(rt)
+		path := openAPIPath(rt.path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*OpenAPIOperation)
+		}
+		doc.Paths[path][strings.ToLower(rt.method)] = doc.operation(rt)
+	}
+	return doc
+}
+
+/*
+ServeOpenAPI - serving OpenAPI document at path.
+Document is YAML if path ends with .yaml or .yml, otherwise JSON
+*/
+func (r *Router) ServeOpenAPI(path string, info OpenAPIInfo) {
+	isYAML := isYAMLFile(path)
+	r.root().router.HandlerFunc("GET", r.fullPath(path), func(w http.ResponseWriter, req *http.Request) {
+		doc := r.OpenAPI(info)
+		var b []byte
+		var err error
+		if isYAML {
+			w.Header().Set("Content-Type", "application/yaml")
+			b, err = doc.YAML()
+		} else {
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			b, err = doc.JSON()
+		}
+		if err != nil {
+			http.Error(w, err.Error(), ErrorServerErrorCode)
+			return
+		}
+		w.Write(b)
+	})
+}
+
+/*
+WriteOpenAPI - writing OpenAPI document to file. YAML for .yaml/.yml files, otherwise JSON
+*/
+func (r *Router) WriteOpenAPI(fileName string, info OpenAPIInfo) error {
+	doc := r.OpenAPI(info)
+	var b []byte
+	var err error
+	if isYAMLFile(fileName) {
+		b, err = doc.YAML()
+	} else {
+		b, err = doc.JSON()
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, b, 0644)
+}
+
+// JSON - document in JSON
+func (doc *OpenAPI) JSON() ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// YAML - document in YAML
+func (doc *OpenAPI) YAML() ([]byte, error) {
+	return yaml.Marshal(doc)
+}
+
+func (doc *OpenAPI) operation(rt route) *OpenAPIOperation {
+	op := &OpenAPIOperation{
+		OperationID: operationID(rt.method, rt.path),
+		Responses:   make(map[string]OpenAPIResponse),
+	}
+	// Path params are taken from route pattern, rules give only their types
+	for _, name := range pathParams(rt.path) {
+		schema := Schema{"type": "string"}
+		if _, v, ok := findRule(rt.rules.Params, name); ok {
+			schema = ruleSchema(v)
+		}
+		op.Parameters = append(op.Parameters, OpenAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   schema,
+		})
+	}
+	for _, name := range sortedRuleNames(rt.rules.Query) {
+		v := rt.rules.Query[name]
+		op.Parameters = append(op.Parameters, OpenAPIParameter{
+			Name:     ruleName(name, v),
+			In:       "query",
+			Required: v.Required,
+			Schema:   ruleSchema(v),
+		})
+	}
+	if len(rt.rules.Body) > 0 {
+		op.RequestBody = &OpenAPIBody{
+			Required: true,
+			Content: map[string]OpenAPIMedia{
+				ContentTypeJSON: {Schema: rulesSchema(rt.rules.Body)},
+			},
+		}
+	}
+	if rt.action == ActionDeleteByID {
+		op.Responses["204"] = OpenAPIResponse{Description: "No content"}
+	} else {
+		op.Responses["200"] = OpenAPIResponse{
+			Description: "OK",
+			Content: map[string]OpenAPIMedia{
+				ContentTypeJSON: {Schema: envelopeSchema(doc.responseSchema(rt))},
+			},
+		}
+	}
+	op.Responses["default"] = OpenAPIResponse{
+		Description: "Error",
+		Content: map[string]OpenAPIMedia{
+			ContentTypeJSON: {Schema: envelopeSchema(Schema{})},
+		},
+	}
+	return op
+}
+
+// responseSchema - schema of response data by resource action and model
+func (doc *OpenAPI) responseSchema(rt route) Schema {
+	if rt.model == nil {
+		return Schema{}
+	}
+	item := doc.modelSchema(reflect.TypeOf(rt.model))
+	switch rt.action {
+	case ActionFind, ActionSave:
+		return Schema{"type": "array", "items": item}
+	}
+	return item
+}
+
+// modelSchema - reference to model schema. Model schema is added to components
+func (doc *OpenAPI) modelSchema(t reflect.Type) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.String() == "time.Time" || t.Name() == "" {
+		return typeSchema(t, doc)
+	}
+	name, ok := doc.schemaNames[t]
+	if !ok {
+		name = doc.schemaName(t)
+		doc.schemaNames[t] = name
+		// Placeholder prevents infinite recursion for self-referencing models
+		doc.Components.Schemas[name] = Schema{}
+		doc.Components.Schemas[name] = structSchema(t, doc)
+	}
+	return Schema{"$ref": "#/components/schemas/" + name}
+}
+
+/*
+schemaName - component name of model: type name, or type name with package (orders.Model)
+if other model has the same name. Number is added if packages have the same name too
+*/
+func (doc *OpenAPI) schemaName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := doc.Components.Schemas[name]; !taken {
+		return name
+	}
+	name = path.Base(t.PkgPath()) + "." + t.Name()
+	unique := name
+	for i := 2; ; i++ {
+		if _, taken := doc.Components.Schemas[unique]; !taken {
+			return unique
+		}
+		unique = name + strconv.Itoa(i)
+	}
+}
+
+func structSchema(t reflect.Type, doc *OpenAPI) Schema {
+	props := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = typeSchema(f.Type, doc)
+	}
+	return Schema{"type": "object", "properties": props}
+}
+
+func typeSchema(t reflect.Type, doc *OpenAPI) Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem(), doc)
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return Schema{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return Schema{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": typeSchema(t.Elem(), doc)}
+	case reflect.Map:
+		return Schema{"type": "object"}
+	case reflect.Struct:
+		if t.String() == "time.Time" {
+			return Schema{"type": "string", "format": "date-time"}
+		}
+		if t.Name() != "" {
+			return doc.modelSchema(t)
+		}
+		return structSchema(t, doc)
+	}
+	return Schema{}
+}
+
+// envelopeSchema - response of default decorator: {"data": ..., "error": ...}
+func envelopeSchema(data Schema) Schema {
+	return Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"data": data,
+			"error": Schema{
+				"type":     "object",
+				"nullable": true,
+				"properties": map[string]interface{}{
+					"code":    Schema{"type": "string"},
+					"message": Schema{"type": "string"},
+					"info":    Schema{},
+				},
+			},
+		},
+	}
+}
+
+// rulesSchema - object schema of body rules
+func rulesSchema(rules map[string]validation) Schema {
+	props := make(map[string]interface{})
+	var required []string
+	for _, key := range sortedRuleNames(rules) {
+		v := rules[key]
+		name := ruleName(key, v)
+		props[name] = ruleSchema(v)
+		if v.Required {
+			required = append(required, name)
+		}
+	}
+	s := Schema{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// ruleSchema - schema of validation type with constraints of extended rules
+func ruleSchema(v validation) Schema {
+	t := v.InputType
+	if strings.HasPrefix(t, "[]") {
+		item := v
+		item.InputType = t[2:]
+		item.MinLength, item.MaxLength = nil, nil
+		s := Schema{"type": "array", "items": ruleSchema(item)}
+		if v.MinLength != nil {
+			s["minItems"] = *v.MinLength
+		}
+		if v.MaxLength != nil {
+			s["maxItems"] = *v.MaxLength
+		}
+		return s
+	}
+	var s Schema
+	switch t {
+	case TypeObject:
+		if v.Properties != nil {
+			return rulesSchema(v.Properties)
+		}
+		return Schema{"type": "object"}
+	case TypeArray:
+		s = Schema{"type": "array", "items": Schema{}}
+		if v.Items != nil {
+			s["items"] = ruleSchema(*v.Items)
+		}
+		if v.MinLength != nil {
+			s["minItems"] = *v.MinLength
+		}
+		if v.MaxLength != nil {
+			s["maxItems"] = *v.MaxLength
+		}
+		return s
+	case "int":
+		s = Schema{"type": "integer"}
+	case "int64":
+		s = Schema{"type": "integer", "format": "int64"}
+	case "float", "float64":
+		s = Schema{"type": "number"}
+	case "bool":
+		s = Schema{"type": "boolean"}
+	default:
+		s = Schema{"type": "string"}
+	}
+	if v.Min != nil {
+		s["minimum"] = *v.Min
+	}
+	if v.Max != nil {
+		s["maximum"] = *v.Max
+	}
+	if v.MinLength != nil {
+		s["minLength"] = *v.MinLength
+	}
+	if v.MaxLength != nil {
+		s["maxLength"] = *v.MaxLength
+	}
+	if v.Pattern != "" {
+		s["pattern"] = v.Pattern
+	}
+	if len(v.Enum) > 0 {
+		s["enum"] = v.Enum
+	}
+	if v.Format != "" {
+		s["format"] = v.Format
+	}
+	if v.Default != nil {
+		s["default"] = v.Default
+	}
+	return s
+}
+
+// ruleName - request field name of rule
+func ruleName(key string, v validation) string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return key
+}
+
+func sortedRuleNames(rules map[string]validation) []string {
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// openAPIPath - converting router path params to OpenAPI: /users/:id -> /users/{id}
+func openAPIPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") || strings.HasPrefix(p, "*") {
+			parts[i] = "{" + p[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// pathParams - names of params of route pattern: /users/:id/files/*path -> id, path
+func pathParams(path string) (names []string) {
+	for _, p := range strings.Split(path, "/") {
+		if strings.HasPrefix(p, ":") || strings.HasPrefix(p, "*") {
+			names = append(names, p[1:])
+		}
+	}
+	return
+}
+
+// operationID - unique operation name by method and path: GET /users/:id -> getUsersId
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, p := range strings.FieldsFunc(path, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		id += strings.ToUpper(p[:1]) + p[1:]
+	}
+	return id
+}
+
+func isYAMLFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,173 @@
+package vodka
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestOpenAPIPaths(t *testing.T) {
+	tests := []struct{ path, openAPI, id string }{
+		{"/users", "/users", "getUsers"},
+		{"/users/:id", "/users/{id}", "getUsersId"},
+		{"/files/*path", "/files/{path}", "getFilesPath"},
+	}
+	for _, tt := range tests {
+		if p := openAPIPath(tt.path); p != tt.openAPI {
+			t.Errorf("openAPIPath(%q) = %q, want %q", tt.path, p, tt.openAPI)
+		}
+		if id := operationID("GET", tt.path); id != tt.id {
+			t.Errorf("operationID(%q) = %q, want %q", tt.path, id, tt.id)
+		}
+	}
+	if names := pathParams("/users/:id/files/*path"); !reflect.DeepEqual(names, []string{"id", "path"}) {
+		t.Errorf("unexpected path params: %v", names)
+	}
+}
+
+func TestOpenAPIOperations(t *testing.T) {
+	app := newTestApp()
+	loadTestRules(t, app, `{
+		"/orders": {
+			"get": {"query": {"status": {"type": "string", "required": true, "enum": ["new", "paid"]}}},
+			"post": {"body": {"amount": {"type": "int", "required": true, "min": 1}, "note": {"type": "string", "maxLength": 10}}}
+		}
+	}`)
+	handler := func(ctx *Context) (interface{}, error) { return nil, nil }
+	app.Router.GET("/orders", handler)
+	app.Router.POST("/orders", handler)
+	app.Router.GET("/orders/:orderId/items/:id", handler)
+	app.Router.Resource("/users", testController{}, ResourceOptions{Model: testUser{}})
+
+	doc := app.Router.OpenAPI(OpenAPIInfo{Title: "Shop", Version: "1.0"})
+	if doc.OpenAPI != openAPIVersion || doc.Info.Title != "Shop" {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+
+	get := doc.Paths["/orders"]["get"]
+	want := []OpenAPIParameter{{Name: "status", In: "query", Required: true, Schema: Schema{"type": "string", "enum": []interface{}{"new", "paid"}}}}
+	if get == nil || !reflect.DeepEqual(get.Parameters, want) {
+		t.Errorf("query parameters:\n got %+v\nwant %+v", get, want)
+	}
+
+	post := doc.Paths["/orders"]["post"]
+	if post == nil || post.RequestBody == nil {
+		t.Fatalf("request body is not described: %+v", post)
+	}
+	body := post.RequestBody.Content[ContentTypeJSON].Schema
+	if !reflect.DeepEqual(body["required"], []string{"amount"}) {
+		t.Errorf("required fields: %v", body["required"])
+	}
+	props := body["properties"].(map[string]interface{})
+	if !reflect.DeepEqual(props["amount"], Schema{"type": "integer", "minimum": 1.0}) {
+		t.Errorf("amount schema: %v", props["amount"])
+	}
+
+	// Params without rules are taken from route pattern as strings
+	items := doc.Paths["/orders/{orderId}/items/{id}"]["get"]
+	if items == nil || len(items.Parameters) != 2 || items.Parameters[0].Name != "orderId" ||
+		items.Parameters[1].In != "path" || !items.Parameters[1].Required ||
+		!reflect.DeepEqual(items.Parameters[1].Schema, Schema{"type": "string"}) {
+		t.Errorf("unexpected path parameters: %+v", items)
+	}
+
+	// Resource params are typed by model key
+	user := doc.Paths["/users/{id}"]["get"]
+	if user == nil || !reflect.DeepEqual(user.Parameters[0].Schema, Schema{"type": "integer", "format": "int64"}) {
+		t.Errorf("unexpected resource parameters: %+v", user)
+	}
+	if _, ok := doc.Paths["/users/{id}"]["delete"].Responses["204"]; !ok {
+		t.Error("delete has no 204 response")
+	}
+	if _, ok := doc.Components.Schemas["testUser"]; !ok {
+		t.Error("model schema is not added to components")
+	}
+}
+
+func TestOpenAPIModelsWithSameName(t *testing.T) {
+	doc := newTestApp().Router.OpenAPI(OpenAPIInfo{})
+	first := doc.modelSchema(reflect.TypeOf(&testUser{}))
+
+	// Models of other packages with the same name
+	type testUser struct {
+		Email string `json:"email"`
+	}
+	second := doc.modelSchema(reflect.TypeOf(testUser{}))
+	var third Schema
+	{
+		type testUser struct{}
+		third = doc.modelSchema(reflect.TypeOf(testUser{}))
+	}
+
+	refs := []interface{}{first["$ref"], second["$ref"], third["$ref"]}
+	want := []interface{}{
+		"#/components/schemas/testUser",
+		"#/components/schemas/vodka.testUser",
+		"#/components/schemas/vodka.testUser2",
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("got refs %v, want %v", refs, want)
+	}
+	if again := doc.modelSchema(reflect.TypeOf(testUser{})); again["$ref"] != second["$ref"] {
+		t.Errorf("model got other name: %v", again)
+	}
+	props := doc.Components.Schemas["vodka.testUser"]["properties"].(map[string]interface{})
+	if _, ok := props["email"]; !ok || len(doc.Components.Schemas) != 3 {
+		t.Errorf("schemas are replaced: %v", doc.Components.Schemas)
+	}
+}
+
+func TestOpenAPIErrorSchemaHasCode(t *testing.T) {
+	app := newTestApp()
+	app.Router.GET("/users", func(ctx *Context) (interface{}, error) { return nil, nil })
+	op := app.Router.OpenAPI(OpenAPIInfo{}).Paths["/users"]["get"]
+	schema := op.Responses["default"].Content[ContentTypeJSON].Schema
+	errSchema := schema["properties"].(map[string]interface{})["error"].(Schema)
+	props := errSchema["properties"].(map[string]interface{})
+	for _, name := range []string{"code", "message", "info"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("error schema has no %q", name)
+		}
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	app := newTestApp()
+	app.Router.GET("/users", func(ctx *Context) (interface{}, error) { return nil, nil })
+	app.Router.ServeOpenAPI("/openapi.json", OpenAPIInfo{Title: "Users"})
+	app.Router.ServeOpenAPI("/openapi.yaml", OpenAPIInfo{Title: "Users"})
+
+	w := serve(app, httptest.NewRequest("GET", "/openapi.json", nil))
+	var doc OpenAPI
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &doc) != nil || doc.Paths["/users"] == nil {
+		t.Errorf("unexpected JSON document %d: %s", w.Code, w.Body.String())
+	}
+	w = serve(app, httptest.NewRequest("GET", "/openapi.yaml", nil))
+	if w.Header().Get("Content-Type") != "application/yaml" || !strings.Contains(w.Body.String(), "openapi: "+openAPIVersion) {
+		t.Errorf("unexpected YAML document: %s", w.Body.String())
+	}
+}
+
+func TestWriteOpenAPI(t *testing.T) {
+	app := newTestApp()
+	app.Router.GET("/users", func(ctx *Context) (interface{}, error) { return nil, nil })
+	fileName := filepath.Join(t.TempDir(), "openapi.yml")
+	if err := app.Router.WriteOpenAPI(fileName, OpenAPIInfo{Title: "Users"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err = yaml.Unmarshal(b, &doc); err != nil || doc["openapi"] != openAPIVersion {
+		t.Errorf("file is not YAML document: %v: %s", err, b)
+	}
+}
@@ -0,0 +1,52 @@
+package vodka
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// PanicHook - called with recovered value and stack when request handling panics
+type PanicHook func(ctx *Context, recovered interface{}, stack []byte)
+
+/*
+OnPanic - setting hook to report panics (error tracker, metrics).
+Hooks are called after panic is recovered and before 500 response is sent
+*/
+func (e *Application) OnPanic(hook PanicHook) {
+	e.panicHooks = append(e.panicHooks, hook)
+}
+
+// recoverPanic - recovering panic in dispatch and sending 500 error through decorator
+func (e *Application) recoverPanic(ctx *Context) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	stack := debug.Stack()
+	log.Printf("Panic: %v\n%s", recovered, stack)
+	for _, hook := range e.panicHooks {
+		e.applyPanicHook(hook, ctx, recovered, stack)
+	}
+	err := Error{
+		httpCode: ErrorServerErrorCode,
+		Code:     ErrServerError.Code,
+		Message:  "internal_error",
+		Info:     "Internal server error",
+	}
+	if isDebug {
+		err.Info = fmt.Sprint(recovered)
+		err.Stack = string(stack)
+	}
+	e.sendResponse(ctx, nil, err)
+}
+
+// applyPanicHook - hook that panics itself must not break response
+func (e *Application) applyPanicHook(hook PanicHook, ctx *Context, recovered interface{}, stack []byte) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Println("Panic hook panics: ", p)
+		}
+	}()
+	hook(ctx, recovered, stack)
+}
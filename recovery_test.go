@@ -0,0 +1,54 @@
+package vodka
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPanicIsSentAsServerError(t *testing.T) {
+	app := newTestApp()
+	var recovered interface{}
+	var stack []byte
+	app.OnPanic(func(ctx *Context, r interface{}, s []byte) {
+		recovered, stack = r, s
+	})
+	app.OnPanic(func(*Context, interface{}, []byte) {
+		panic("hook fails")
+	})
+	app.Router.GET("/panic", func(ctx *Context) (interface{}, error) {
+		var m map[string]int
+		m["x"] = 1
+		return nil, nil
+	})
+
+	w := serve(app, httptest.NewRequest("GET", "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+	if e := responseError(t, w); e.Code != ErrServerError.Code || e.Message != "internal_error" {
+		t.Errorf("unexpected error: %+v", e)
+	}
+	if recovered == nil || len(stack) == 0 {
+		t.Errorf("panic hook is not called: %v", recovered)
+	}
+}
+
+func TestPanicInMiddlewareIsRecovered(t *testing.T) {
+	app := newTestApp()
+	app.Use(func(ctx *Context) (*Context, error) {
+		panic("middleware")
+	})
+	app.Router.GET("/items", func(ctx *Context) (interface{}, error) {
+		t.Error("handler is called after panic")
+		return nil, nil
+	})
+
+	w := serve(app, httptest.NewRequest("GET", "/items", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+	if e := responseError(t, w); e.Info != "Internal server error" || e.Stack != nil {
+		t.Errorf("panic details are sent without debug: %+v", e)
+	}
+}
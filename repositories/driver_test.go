@@ -0,0 +1,275 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/syndicatedb/vodka/adapters"
+	"github.com/syndicatedb/vodka/builders"
+)
+
+// testDB - database of test driver: records queries and returns queued results
+type testDB struct {
+	mu        sync.Mutex
+	queries   []testQuery
+	results   []testRows
+	err       error
+	commitErr error
+	commits   int
+	rollbacks int
+}
+
+// testQuery - query that is run by repository
+type testQuery struct {
+	SQL  string
+	Args []interface{}
+	InTx bool
+}
+
+// testRows - result of query
+type testRows struct {
+	columns []string
+	rows    [][]driver.Value
+	i       int
+}
+
+var (
+	testDBs      sync.Map
+	testDBSeq    int64
+	registerOnce sync.Once
+)
+
+// newTestDB - test database and adapter that builds SQL with builder
+func newTestDB(t *testing.T, builder func() builders.Builder) (*testDB, *testAdapter) {
+	registerOnce.Do(func() { sql.Register("vodkatest", testDriver{}) })
+	db := &testDB{}
+	dsn := fmt.Sprintf("db%d", atomic.AddInt64(&testDBSeq, 1))
+	testDBs.Store(dsn, db)
+	conn, err := sql.Open("vodkatest", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		testDBs.Delete(dsn)
+	})
+	return db, &testAdapter{conn: conn, builder: builder}
+}
+
+// push - queueing result of next query
+func (db *testDB) push(columns []string, rows ...[]driver.Value) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.results = append(db.results, testRows{columns: columns, rows: rows})
+}
+
+// last - last query that was run
+func (db *testDB) last(t *testing.T) testQuery {
+	t.Helper()
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if len(db.queries) == 0 {
+		t.Fatal("no queries were run")
+	}
+	return db.queries[len(db.queries)-1]
+}
+
+func (db *testDB) run(query string, args []driver.NamedValue, inTx bool) (testRows, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	q := testQuery{SQL: query, InTx: inTx}
+	for _, a := range args {
+		q.Args = append(q.Args, a.Value)
+	}
+	db.queries = append(db.queries, q)
+	if db.err != nil {
+		return testRows{}, db.err
+	}
+	if len(db.results) == 0 {
+		return testRows{}, nil
+	}
+	res := db.results[0]
+	db.results = db.results[1:]
+	return res, nil
+}
+
+type testDriver struct{}
+
+func (testDriver) Open(dsn string) (driver.Conn, error) {
+	db, ok := testDBs.Load(dsn)
+	if !ok {
+		return nil, errors.New("unknown test database " + dsn)
+	}
+	return &testConn{db: db.(*testDB)}, nil
+}
+
+type testConn struct {
+	db   *testDB
+	inTx bool
+}
+
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	c.inTx = true
+	return &testTx{conn: c}, nil
+}
+
+func (c *testConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := c.db.run(query, args, c.inTx)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *testConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := c.db.run(query, args, c.inTx); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type testTx struct {
+	conn *testConn
+}
+
+func (tx *testTx) Commit() error {
+	tx.conn.inTx = false
+	tx.conn.db.mu.Lock()
+	defer tx.conn.db.mu.Unlock()
+	tx.conn.db.commits++
+	return tx.conn.db.commitErr
+}
+
+func (tx *testTx) Rollback() error {
+	tx.conn.inTx = false
+	tx.conn.db.mu.Lock()
+	defer tx.conn.db.mu.Unlock()
+	tx.conn.db.rollbacks++
+	return nil
+}
+
+func (r *testRows) Columns() []string {
+	return r.columns
+}
+
+func (r *testRows) Close() error {
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.i])
+	r.i++
+	return nil
+}
+
+// testAdapter - SQL adapter over test database
+type testAdapter struct {
+	conn    *sql.DB
+	builder func() builders.Builder
+}
+
+func (a *testAdapter) Connect() error { return nil }
+func (a *testAdapter) Close() error   { return nil }
+
+func (a *testAdapter) Builder() builders.Builder {
+	return a.builder()
+}
+
+func (a *testAdapter) Exec(SQL string, args ...interface{}) (sql.Result, error) {
+	return a.ExecContext(context.Background(), SQL, args...)
+}
+
+func (a *testAdapter) Query(SQL string, args ...interface{}) (*sql.Rows, error) {
+	return a.QueryContext(context.Background(), SQL, args...)
+}
+
+func (a *testAdapter) QueryRow(SQL string, args ...interface{}) (*sql.Row, error) {
+	return a.QueryRowContext(context.Background(), SQL, args...)
+}
+
+func (a *testAdapter) ExecContext(ctx context.Context, SQL string, args ...interface{}) (sql.Result, error) {
+	return a.conn.ExecContext(ctx, SQL, args...)
+}
+
+func (a *testAdapter) QueryContext(ctx context.Context, SQL string, args ...interface{}) (*sql.Rows, error) {
+	return a.conn.QueryContext(ctx, SQL, args...)
+}
+
+func (a *testAdapter) QueryRowContext(ctx context.Context, SQL string, args ...interface{}) (*sql.Row, error) {
+	return a.conn.QueryRowContext(ctx, SQL, args...), nil
+}
+
+func (a *testAdapter) Begin() (adapters.Tx, error) {
+	return a.BeginTx(context.Background(), nil)
+}
+
+func (a *testAdapter) BeginTx(ctx context.Context, opts *sql.TxOptions) (adapters.Tx, error) {
+	tx, err := a.conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &testTxAdapter{tx: tx, builder: a.builder}, nil
+}
+
+// testTxAdapter - adapter bound to transaction of test database
+type testTxAdapter struct {
+	tx      *sql.Tx
+	builder func() builders.Builder
+}
+
+func (a *testTxAdapter) Connect() error  { return nil }
+func (a *testTxAdapter) Close() error    { return nil }
+func (a *testTxAdapter) Commit() error   { return a.tx.Commit() }
+func (a *testTxAdapter) Rollback() error { return a.tx.Rollback() }
+
+func (a *testTxAdapter) Builder() builders.Builder {
+	return a.builder()
+}
+
+func (a *testTxAdapter) Exec(SQL string, args ...interface{}) (sql.Result, error) {
+	return a.tx.Exec(SQL, args...)
+}
+
+func (a *testTxAdapter) Query(SQL string, args ...interface{}) (*sql.Rows, error) {
+	return a.tx.Query(SQL, args...)
+}
+
+func (a *testTxAdapter) QueryRow(SQL string, args ...interface{}) (*sql.Row, error) {
+	return a.tx.QueryRow(SQL, args...), nil
+}
+
+func (a *testTxAdapter) ExecContext(ctx context.Context, SQL string, args ...interface{}) (sql.Result, error) {
+	return a.tx.ExecContext(ctx, SQL, args...)
+}
+
+func (a *testTxAdapter) QueryContext(ctx context.Context, SQL string, args ...interface{}) (*sql.Rows, error) {
+	return a.tx.QueryContext(ctx, SQL, args...)
+}
+
+func (a *testTxAdapter) QueryRowContext(ctx context.Context, SQL string, args ...interface{}) (*sql.Row, error) {
+	return a.tx.QueryRowContext(ctx, SQL, args...), nil
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/syndicatedb/vodka"
+)
+
+// Error codes of constraint violations and failed transactions
+var (
+	ErrUniqueViolation      = vodka.RegisterError("unique_violation", vodka.ErrorConflictCode, "Item already exists")
+	ErrForeignKeyViolation  = vodka.RegisterError("foreign_key_violation", vodka.ErrorUnprocessableCode, "Related item is not found or is in use")
+	ErrNotNullViolation     = vodka.RegisterError("not_null_violation", vodka.ErrorBadRequestCode, "Required field is not set")
+	ErrSerializationFailure = vodka.RegisterError("serialization_failure", vodka.ErrorUnavailableCode, "Transaction is failed, retry request")
+)
+
+/*
+ErrorTranslator - converting driver error of repository to error that is sent to client.
+Custom translator can fall back to TranslateError
+*/
+type ErrorTranslator func(err error) error
+
+/*
+ErrorInfo - info of translated driver error. Values of columns are not included
+*/
+type ErrorInfo struct {
+	Table      string   `json:"table,omitempty"`
+	Constraint string   `json:"constraint,omitempty"`
+	Columns    []string `json:"columns,omitempty"`
+	Retryable  bool     `json:"retryable,omitempty"`
+}
+
+// PostgreSQL error codes
+const (
+	pqNotNullViolation     = "23502"
+	pqForeignKeyViolation  = "23503"
+	pqUniqueViolation      = "23505"
+	pqSerializationFailure = "40001"
+	pqDeadlockDetected     = "40P01"
+)
+
+// MySQL error numbers
+const (
+	myLockWaitTimeout  = 1205
+	myDeadlock         = 1213
+	myBadNull          = 1048
+	myNoDefault        = 1364
+	myDupEntry         = 1062
+	myRowIsReferenced  = 1451
+	myNoReferencedRow  = 1452
+	myNoReferencedRow2 = 1216
+	myRowIsReferenced2 = 1217
+)
+
+var (
+	// Key (email, tenant_id)=(...) already exists.
+	pqKeyColumns = regexp.MustCompile(`Key \(([^)]+)\)=`)
+	// Duplicate entry '...' for key 'users.email'
+	myDupKey = regexp.MustCompile(`for key '([^']+)'`)
+	// CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+	myForeignKey = regexp.MustCompile("CONSTRAINT `([^`]+)` FOREIGN KEY \\(([^)]+)\\)")
+	// Column 'name' cannot be null, Field 'name' doesn't have a default value
+	myColumn = regexp.MustCompile(`(?:Column|Field) '([^']+)'`)
+)
+
+/*
+TranslateError - default translation of lib/pq and go-sql-driver/mysql errors:
+unique violation is 409, foreign key violation is 422, not-null violation is 400,
+serialization failure and deadlock are 503 and can be retried. Other errors are returned as is
+*/
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return translatePostgres(err, pqErr)
+	}
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return translateMySQL(err, myErr)
+	}
+	return err
+}
+
+func translatePostgres(err error, pqErr *pq.Error) error {
+	info := ErrorInfo{Table: pqErr.Table, Constraint: pqErr.Constraint}
+	switch string(pqErr.Code) {
+	case pqUniqueViolation:
+		info.Columns = keyColumns(pqErr.Detail)
+		return ErrUniqueViolation.Wrap(err, info)
+	case pqForeignKeyViolation:
+		info.Columns = keyColumns(pqErr.Detail)
+		return ErrForeignKeyViolation.Wrap(err, info)
+	case pqNotNullViolation:
+		if pqErr.Column != "" {
+			info.Columns = []string{pqErr.Column}
+		}
+		return ErrNotNullViolation.Wrap(err, info)
+	case pqSerializationFailure, pqDeadlockDetected:
+		return ErrSerializationFailure.Wrap(err, ErrorInfo{Retryable: true})
+	}
+	return err
+}
+
+func translateMySQL(err error, myErr *mysql.MySQLError) error {
+	switch myErr.Number {
+	case myDupEntry:
+		var info ErrorInfo
+		if m := myDupKey.FindStringSubmatch(myErr.Message); m != nil {
+			// MySQL 8 prefixes key with table name
+			info.Constraint = m[1]
+			if i := strings.LastIndex(m[1], "."); i >= 0 {
+				info.Table, info.Constraint = m[1][:i], m[1][i+1:]
+			}
+		}
+		return ErrUniqueViolation.Wrap(err, info)
+	case myRowIsReferenced, myNoReferencedRow, myRowIsReferenced2, myNoReferencedRow2:
+		var info ErrorInfo
+		if m := myForeignKey.FindStringSubmatch(myErr.Message); m != nil {
+			info.Constraint = m[1]
+			info.Columns = splitColumns(strings.Replace(m[2], "`", "", -1))
+		}
+		return ErrForeignKeyViolation.Wrap(err, info)
+	case myBadNull, myNoDefault:
+		var info ErrorInfo
+		if m := myColumn.FindStringSubmatch(myErr.Message); m != nil {
+			info.Columns = []string{m[1]}
+		}
+		return ErrNotNullViolation.Wrap(err, info)
+	case myDeadlock, myLockWaitTimeout:
+		return ErrSerializationFailure.Wrap(err, ErrorInfo{Retryable: true})
+	}
+	return err
+}
+
+// keyColumns - columns of "Key (a, b)=(...)" detail of PostgreSQL error
+func keyColumns(detail string) []string {
+	m := pqKeyColumns.FindStringSubmatch(detail)
+	if m == nil {
+		return nil
+	}
+	return splitColumns(m[1])
+}
+
+func splitColumns(s string) []string {
+	var columns []string
+	for _, c := range strings.Split(s, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			columns = append(columns, strings.Trim(c, `"`))
+		}
+	}
+	return columns
+}
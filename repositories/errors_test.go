@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/syndicatedb/vodka"
+	"github.com/syndicatedb/vodka/builders"
+)
+
+func TestTranslatePostgresErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *pq.Error
+		code *vodka.ErrorCode
+		info ErrorInfo
+	}{
+		{
+			"unique",
+			&pq.Error{Code: pqUniqueViolation, Table: "users", Constraint: "users_email_key", Detail: `Key (email, "tenant_id")=(a@b.c, 1) already exists.`},
+			ErrUniqueViolation,
+			ErrorInfo{Table: "users", Constraint: "users_email_key", Columns: []string{"email", "tenant_id"}},
+		},
+		{
+			"foreign key",
+			&pq.Error{Code: pqForeignKeyViolation, Table: "orders", Constraint: "orders_user_id_fkey", Detail: "Key (user_id)=(5) is not present in table \"users\"."},
+			ErrForeignKeyViolation,
+			ErrorInfo{Table: "orders", Constraint: "orders_user_id_fkey", Columns: []string{"user_id"}},
+		},
+		{
+			"not null",
+			&pq.Error{Code: pqNotNullViolation, Table: "users", Column: "name"},
+			ErrNotNullViolation,
+			ErrorInfo{Table: "users", Columns: []string{"name"}},
+		},
+		{"serialization", &pq.Error{Code: pqSerializationFailure}, ErrSerializationFailure, ErrorInfo{Retryable: true}},
+		{"deadlock", &pq.Error{Code: pqDeadlockDetected}, ErrSerializationFailure, ErrorInfo{Retryable: true}},
+	}
+	for _, tt := range tests {
+		checkTranslated(t, tt.name, fmt.Errorf("query: %w", tt.err), tt.err, tt.code, tt.info)
+	}
+}
+
+func TestTranslateMySQLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *mysql.MySQLError
+		code *vodka.ErrorCode
+		info ErrorInfo
+	}{
+		{
+			"unique of MySQL 8",
+			&mysql.MySQLError{Number: myDupEntry, Message: "Duplicate entry 'a@b.c' for key 'users.email'"},
+			ErrUniqueViolation,
+			ErrorInfo{Table: "users", Constraint: "email"},
+		},
+		{
+			"unique",
+			&mysql.MySQLError{Number: myDupEntry, Message: "Duplicate entry 'a@b.c' for key 'email'"},
+			ErrUniqueViolation,
+			ErrorInfo{Constraint: "email"},
+		},
+		{
+			"foreign key",
+			&mysql.MySQLError{Number: myNoReferencedRow, Message: "Cannot add or update a child row: a foreign key constraint fails (`shop`.`orders`, CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))"},
+			ErrForeignKeyViolation,
+			ErrorInfo{Constraint: "fk_user", Columns: []string{"user_id"}},
+		},
+		{
+			"not null",
+			&mysql.MySQLError{Number: myBadNull, Message: "Column 'name' cannot be null"},
+			ErrNotNullViolation,
+			ErrorInfo{Columns: []string{"name"}},
+		},
+		{
+			"no default",
+			&mysql.MySQLError{Number: myNoDefault, Message: "Field 'name' doesn't have a default value"},
+			ErrNotNullViolation,
+			ErrorInfo{Columns: []string{"name"}},
+		},
+		{"deadlock", &mysql.MySQLError{Number: myDeadlock}, ErrSerializationFailure, ErrorInfo{Retryable: true}},
+		{"lock wait", &mysql.MySQLError{Number: myLockWaitTimeout}, ErrSerializationFailure, ErrorInfo{Retryable: true}},
+	}
+	for _, tt := range tests {
+		checkTranslated(t, tt.name, tt.err, tt.err, tt.code, tt.info)
+	}
+}
+
+func checkTranslated(t *testing.T, name string, err, driverErr error, code *vodka.ErrorCode, info ErrorInfo) {
+	t.Helper()
+	translated := TranslateError(err)
+	var e vodka.Error
+	if !errors.As(translated, &e) || e.Status() != code.Status || !errors.Is(translated, code) {
+		t.Errorf("%s: expected %s, got %#v", name, code.Code, translated)
+		return
+	}
+	if !errors.Is(translated, driverErr) {
+		t.Errorf("%s: driver error is not wrapped", name)
+	}
+	if !reflect.DeepEqual(e.Info, info) {
+		t.Errorf("%s: info:\n got %+v\nwant %+v", name, e.Info, info)
+	}
+}
+
+func TestTranslateOtherErrors(t *testing.T) {
+	other := &pq.Error{Code: "42601", Message: "syntax error"}
+	if TranslateError(other) != error(other) {
+		t.Error("unknown postgres error is changed")
+	}
+	plain := errors.New("connection refused")
+	if TranslateError(plain) != plain || TranslateError(nil) != nil {
+		t.Error("error of other driver is changed")
+	}
+}
+
+func TestPostgresTranslatesQueryErrors(t *testing.T) {
+	db, adapter := newTestDB(t, builders.NewPostgres)
+	repo := NewPostgres(adapter, "orders", &testOrder{})
+	db.err = &pq.Error{Code: pqUniqueViolation, Constraint: "orders_pkey"}
+
+	if _, err := repo.Create(map[string]interface{}{"status": "new"}); !errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("expected unique violation, got %v", err)
+	}
+
+	errCustom := errors.New("custom")
+	repo.SetErrorTranslator(func(err error) error {
+		if errors.Is(TranslateError(err), ErrUniqueViolation) {
+			return errCustom
+		}
+		return TranslateError(err)
+	})
+	if _, err := repo.DeleteByID(1); err != errCustom {
+		t.Errorf("translator of repository is not used: %v", err)
+	}
+}
@@ -1,11 +1,17 @@
 package repositories
 
 import (
+	"bytes"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/syndicatedb/vodka"
 	"github.com/syndicatedb/vodka/builders"
 )
 
@@ -105,7 +111,11 @@ func getBool(v interface{}) bool {
 	return false
 }
 
-func parseParams(params interface{}) (m QueryModificator) {
+/*
+parseParams - query modificator from params. Columns of orderBy ("created_at,id")
+are checked because they are written into SQL, invalid one is 400 error
+*/
+func parseParams(params interface{}) (m QueryModificator, err error) {
 	if params == nil {
 		return
 	}
@@ -121,19 +131,18 @@ func parseParams(params interface{}) (m QueryModificator) {
 			m.limit = p["limit"].(int)
 		}
 		if p["orderBy"] != nil {
-			var orderParams builders.OrderParam
-			var orderParamsArr []builders.OrderParam
-
-			orderParams.OrderBy = p["orderBy"].(string)
-
-			if p["order"] == "asc" {
-				orderParams.Asc = true
-			} else {
-				orderParams.Desc = true
+			desc := p["order"] != "asc"
+			for _, column := range strings.Split(p["orderBy"].(string), ",") {
+				column = strings.TrimSpace(column)
+				if !builders.IsIdentifier(column) {
+					return m, vodka.NewBadRequestError("order_not_valid", fmt.Sprintf("Column %q can't be used in order", column))
+				}
+				m.orderBy = append(m.orderBy, builders.OrderParam{
+					OrderBy: column,
+					Asc:     !desc,
+					Desc:    desc,
+				})
 			}
-
-			orderParamsArr = append(orderParamsArr, orderParams)
-			m.orderBy = orderParamsArr
 		}
 		if p["onConflictConstraint"] != nil {
 			m.OnConflictConstraint = p["onConflictConstraint"].(string)
@@ -141,6 +150,107 @@ func parseParams(params interface{}) (m QueryModificator) {
 		if p["onConflictAction"] != nil {
 			m.onConflictAction = p["onConflictAction"].(string)
 		}
+		if p["cursor"] != nil {
+			m.cursor = p["cursor"].(string)
+		}
 	}
 	return
 }
+
+// scanRows - scanning rows into maps column=value
+func scanRows(rows *sql.Rows) ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+	cols, _ := rows.Columns()
+	dest := make([]interface{}, len(cols))
+	rawResult := make([]interface{}, len(cols))
+
+	for c := range cols {
+		dest[c] = &rawResult[c]
+	}
+
+	for rows.Next() {
+		data := make(map[string]interface{})
+		if err := rows.Scan(dest...); err != nil {
+			fmt.Println("Error: ", err)
+			return nil, err
+		}
+		for key, v := range cols {
+			if a, ok := rawResult[key].([]byte); ok == true {
+				f, e := strconv.ParseFloat(string(a), 64)
+				if e != nil {
+					data[v] = string(a)
+				} else {
+					data[v] = f
+				}
+			} else {
+				data[v] = rawResult[key]
+			}
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
+
+// keysetOrder - adding primary key to order so every row has unique position
+func keysetOrder(order []builders.OrderParam, key string) []builders.OrderParam {
+	desc := false
+	for _, o := range order {
+		if o.OrderBy == key {
+			return order
+		}
+		desc = o.Desc
+	}
+	return append(order, builders.OrderParam{
+		OrderBy: key,
+		Asc:     !desc,
+		Desc:    desc,
+	})
+}
+
+/*
+keysetColumns - columns of keyset and direction. Row comparison (a, b) > (x, y) has one direction,
+so order with mixed directions can't be used for cursor
+*/
+func keysetColumns(order []builders.OrderParam) (columns []string, desc bool, err error) {
+	for i, o := range order {
+		if i == 0 {
+			desc = o.Desc
+		} else if o.Desc != desc {
+			return nil, false, vodka.NewBadRequestError("order_not_valid", "Cursor can't be used with mixed order directions")
+		}
+		columns = append(columns, o.OrderBy)
+	}
+	return
+}
+
+// encodeCursor - opaque cursor with values of keyset columns of the row
+func encodeCursor(row map[string]interface{}, columns []string) string {
+	var values []interface{}
+	for _, c := range columns {
+		if i := strings.LastIndex(c, "."); i != -1 {
+			c = c[i+1:]
+		}
+		values = append(values, row[c])
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor - values of keyset columns from cursor
+func decodeCursor(cursor string, size int) ([]interface{}, error) {
+	var values []interface{}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		// numbers are kept as json.Number to not lose precision of big keys
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	}
+	if err != nil || len(values) != size {
+		return nil, vodka.NewBadRequestError("cursor_not_valid", "Cursor doesn't match order")
+	}
+	return values, nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/syndicatedb/vodka"
+	"github.com/syndicatedb/vodka/builders"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	row := map[string]interface{}{"created_at": "2020-01-02", "id": int64(9007199254740993)}
+	cursor := encodeCursor(row, []string{"t.created_at", "id"})
+	values, err := decodeCursor(cursor, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []interface{}{"2020-01-02", json.Number("9007199254740993")}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("got %#v, want %#v", values, want)
+	}
+}
+
+func TestDecodeCursorErrors(t *testing.T) {
+	valid := encodeCursor(map[string]interface{}{"id": 1}, []string{"id"})
+	for name, cursor := range map[string]string{
+		"not base64":      "!!!",
+		"not json":        "bm90IGpzb24",
+		"size mismatch":   valid,
+		"empty array":     encodeCursor(nil, nil),
+		"wrong json type": "eyJpZCI6MX0",
+	} {
+		_, err := decodeCursor(cursor, 2)
+		if !errors.As(err, new(vodka.Error)) {
+			t.Errorf("%s: expected 400 error, got %v", name, err)
+		}
+	}
+}
+
+func TestKeysetOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []builders.OrderParam
+		want  []builders.OrderParam
+	}{
+		{"empty", nil, []builders.OrderParam{{OrderBy: "id", Asc: true}}},
+		{
+			"key follows direction",
+			[]builders.OrderParam{{OrderBy: "created_at", Desc: true}},
+			[]builders.OrderParam{{OrderBy: "created_at", Desc: true}, {OrderBy: "id", Desc: true}},
+		},
+		{
+			"key is already in order",
+			[]builders.OrderParam{{OrderBy: "id", Desc: true}, {OrderBy: "name", Desc: true}},
+			[]builders.OrderParam{{OrderBy: "id", Desc: true}, {OrderBy: "name", Desc: true}},
+		},
+	}
+	for _, tt := range tests {
+		if got := keysetOrder(tt.order, "id"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestKeysetColumns(t *testing.T) {
+	columns, desc, err := keysetColumns([]builders.OrderParam{{OrderBy: "created_at", Desc: true}, {OrderBy: "id", Desc: true}})
+	if err != nil || !desc || !reflect.DeepEqual(columns, []string{"created_at", "id"}) {
+		t.Errorf("unexpected result: %v %v %v", columns, desc, err)
+	}
+	_, _, err = keysetColumns([]builders.OrderParam{{OrderBy: "created_at", Desc: true}, {OrderBy: "id", Asc: true}})
+	if !errors.Is(err, vodka.ErrBadRequest) {
+		t.Errorf("expected 400 error for mixed directions, got %v", err)
+	}
+}
+
+func TestParseParamsOrder(t *testing.T) {
+	m, err := parseParams(ParamsMap{"orderBy": "created_at, id", "order": "asc", "limit": 5, "cursor": "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []builders.OrderParam{{OrderBy: "created_at", Asc: true}, {OrderBy: "id", Asc: true}}
+	if !reflect.DeepEqual(m.orderBy, want) || m.limit != 5 || m.cursor != "abc" {
+		t.Errorf("unexpected modificator: %+v", m)
+	}
+
+	_, err = parseParams(ParamsMap{"orderBy": "id; DROP TABLE users"})
+	var e vodka.Error
+	if !errors.As(err, &e) || e.Status() != vodka.ErrorBadRequestCode || e.Message != "order_not_valid" {
+		t.Errorf("expected order_not_valid error, got %v", err)
+	}
+}
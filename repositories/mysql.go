@@ -1,12 +1,12 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"reflect"
-	"strconv"
 
 	lib "github.com/niklucky/go-lib"
 	uuid "github.com/nu7hatch/gouuid"
@@ -26,6 +26,8 @@ type MySQL struct {
 	mapper             Mapper
 	debug              bool
 	joinedRepositories map[string]builders.Join
+	ctx                context.Context
+	translator         ErrorTranslator
 }
 
 /*
@@ -49,6 +51,22 @@ func (ds *MySQL) SetMapper(m Mapper) {
 	ds.mapper = m
 }
 
+/*
+SetErrorTranslator - setting translator of driver errors.
+By default TranslateError is used
+*/
+func (ds *MySQL) SetErrorTranslator(t ErrorTranslator) {
+	ds.translator = t
+}
+
+// translate - error of query that is returned to service
+func (ds *MySQL) translate(err error) error {
+	if ds.translator != nil {
+		return ds.translator(err)
+	}
+	return TranslateError(err)
+}
+
 // Join - joining source to main.
 /*
 @param joinSource name of source to be joined: JOIN joinSource
@@ -65,6 +83,34 @@ func (ds *MySQL) Join(source, key, targetKey, joinType string, fields []string)
 	}
 }
 
+/*
+WithTx - returns copy of repository bound to transaction.
+Original repository keeps using its own adapter
+*/
+func (ds *MySQL) WithTx(tx adapters.Tx) Recorder {
+	repo := *ds
+	repo.adapter = tx
+	return &repo
+}
+
+/*
+WithContext - returns copy of repository that runs queries with ctx.
+Queries are cancelled when ctx is done
+*/
+func (ds *MySQL) WithContext(ctx context.Context) Recorder {
+	repo := *ds
+	repo.ctx = ctx
+	return &repo
+}
+
+// context - context of queries. Background if repository is not bound to context
+func (ds *MySQL) context() context.Context {
+	if ds.ctx == nil {
+		return context.Background()
+	}
+	return ds.ctx
+}
+
 /*
 Create - save data to Storage with Adapter
 */
@@ -84,15 +130,18 @@ func (ds *MySQL) Create(data interface{}) (interface{}, error) {
 	// Starting to build INSERT query
 	builder := ds.adapter.Builder()
 	builder.Insert(ds.source).Values(data)
-	SQL := builder.Build()
+	SQL, args, err := builder.BuildWithArgs()
+	if err != nil {
+		return nil, err
+	}
 
 	if ds.debug {
-		fmt.Println("Create SQL: ", SQL)
+		fmt.Println("Create SQL: ", SQL, args)
 	}
-	fmt.Println("SQL is: ", SQL)
-	result, err := ds.adapter.Exec(SQL)
+	fmt.Println("SQL is: ", SQL, args)
+	result, err := ds.adapter.ExecContext(ds.context(), SQL, args...)
 	if err != nil {
-		return nil, err
+		return nil, ds.translate(err)
 	}
 	// We have auto increment id that is returned
 	if id, err := result.LastInsertId(); err == nil {
@@ -108,8 +157,12 @@ func (ds *MySQL) Create(data interface{}) (interface{}, error) {
 
 func (ds *MySQL) Save(data interface{}, params ParamsMap) (interface{}, error) {
 	var SQL string
+	var args []interface{}
 	qb := ds.adapter.Builder()
-	mod := parseParams(params)
+	mod, err := parseParams(params)
+	if err != nil {
+		return nil, err
+	}
 
 	// Checking for auto generated uuid. If found — generating
 	uuidx := ds.generateUUID()
@@ -125,20 +178,26 @@ func (ds *MySQL) Save(data interface{}, params ParamsMap) (interface{}, error) {
 		fields := lib.GetStructTags(reflect.ValueOf(ds.model).Elem(), "unique", true)
 		qb.Save(ds.source).Values(data)
 		qb.OnConflictFields(fields).OnConflictAction(mod.onConflictAction)
-		SQL = qb.Build()
+		SQL, args, err = qb.BuildWithArgs()
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		qb.Save(ds.source).Values(data)
 		qb.OnConflictConstraint(mod.OnConflictConstraint)
 		qb.OnConflictAction(mod.onConflictAction)
-		SQL = qb.Build()
+		SQL, args, err = qb.BuildWithArgs()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if ds.debug {
-		fmt.Println("Create SQL: ", SQL)
+		fmt.Println("Create SQL: ", SQL, args)
 	}
-	result, err := ds.adapter.Exec(SQL)
+	result, err := ds.adapter.ExecContext(ds.context(), SQL, args...)
 	if err != nil {
-		return nil, err
+		return nil, ds.translate(err)
 	}
 	log.Printf("Result: %+v\n", result)
 	var id int64
@@ -181,14 +240,17 @@ Delete - deleteing from storage by query
 */
 func (ds *MySQL) Delete(q QueryMap) (interface{}, error) {
 	builder := ds.adapter.Builder()
-	SQL := builder.Delete().From(ds.source).Where(q).Build()
+	SQL, args, err := builder.Delete().From(ds.source).Where(q).BuildWithArgs()
+	if err != nil {
+		return nil, err
+	}
 	if ds.debug {
-		fmt.Println("Delete SQL: ", SQL)
+		fmt.Println("Delete SQL: ", SQL, args)
 	}
 
-	rows, err := ds.adapter.Exec(SQL)
+	rows, err := ds.adapter.ExecContext(ds.context(), SQL, args...)
 	if err != nil {
-		return nil, err
+		return nil, ds.translate(err)
 	}
 	return rows, nil
 }
@@ -200,13 +262,16 @@ func (ds *MySQL) DeleteByID(id interface{}) (interface{}, error) {
 	builder := ds.adapter.Builder()
 	q := make(map[string]interface{})
 	q["id"] = id
-	SQL := builder.Delete().From(ds.source).Where(q).Build()
+	SQL, args, err := builder.Delete().From(ds.source).Where(q).BuildWithArgs()
+	if err != nil {
+		return nil, err
+	}
 	if ds.debug {
-		fmt.Println("DeleteByID SQL: ", SQL)
+		fmt.Println("DeleteByID SQL: ", SQL, args)
 	}
-	result, err := ds.adapter.Exec(SQL)
+	result, err := ds.adapter.ExecContext(ds.context(), SQL, args...)
 	if err != nil {
-		return nil, err
+		return nil, ds.translate(err)
 	}
 	return result, nil
 }
@@ -216,13 +281,16 @@ Update - updating item in storage by query and payload
 */
 func (ds *MySQL) Update(q QueryMap, payload map[string]interface{}) (interface{}, error) {
 	builder := ds.adapter.Builder()
-	SQL := builder.Update(ds.source).Set(payload).Where(q).Limit(1, 0).Build()
+	SQL, args, err := builder.Update(ds.source).Set(payload).Where(q).Limit(1, 0).BuildWithArgs()
+	if err != nil {
+		return nil, err
+	}
 	if ds.debug {
-		fmt.Println("Update SQL: ", SQL)
+		fmt.Println("Update SQL: ", SQL, args)
 	}
-	_, err := ds.adapter.Exec(SQL)
+	_, err = ds.adapter.ExecContext(ds.context(), SQL, args...)
 	if err != nil {
-		return nil, err
+		return nil, ds.translate(err)
 	}
 	var p ParamsMap
 	// Checking for updated fields
@@ -252,6 +320,89 @@ func (ds *MySQL) Find(query QueryMap, params ParamsMap) (interface{}, error) {
 	return result, err
 }
 
+/*
+FindWhere - Finding data by condition: builders.Or(builders.Cond("owner", "=", id), builders.Cond("shared", "=", true))
+Will return Collection
+*/
+func (ds *MySQL) FindWhere(condition builders.Condition, params ParamsMap) (interface{}, error) {
+	rows, err := ds.fetch(nil, params, condition)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ds.mapCollection(rows)
+	if d, ok := result.([]interface{}); ok {
+		if len(d) == 0 {
+			return make([]int, 0), err
+		}
+	}
+	return result, err
+}
+
+/*
+FindAfter - keyset (cursor) pagination. Finding rows after params["cursor"]
+ordered by params["orderBy"] and primary key. Returns collection and cursor for the next page,
+cursor is empty if there are no more rows
+*/
+func (ds *MySQL) FindAfter(query QueryMap, params ParamsMap) (interface{}, string, error) {
+	mod, err := parseParams(params)
+	if err != nil {
+		return nil, "", err
+	}
+	mod.keyset = true
+	if mod.limit == 0 {
+		mod.limit = DefaultLimit
+	}
+	limit := mod.limit
+	// Fetching one more row to know if there is next page
+	mod.limit++
+	data, err := ds.fetchData(query, mod)
+	if err != nil {
+		return nil, "", err
+	}
+	var next string
+	if len(data) > limit {
+		data = data[:limit]
+		columns, _, _ := keysetColumns(keysetOrder(mod.orderBy, ds.primaryKey()))
+		next = encodeCursor(data[len(data)-1], columns)
+	}
+	result, err := ds.mapCollection(ds.populate(data))
+	if d, ok := result.([]interface{}); ok {
+		if len(d) == 0 {
+			return make([]int, 0), "", err
+		}
+	}
+	return result, next, err
+}
+
+/*
+Count - counting rows by query (map key=value)
+*/
+func (ds *MySQL) Count(query QueryMap) (int64, error) {
+	qb := ds.adapter.Builder()
+	qb.Count().From(ds.source).Where(query)
+	if len(ds.joinedRepositories) > 0 {
+		for _, j := range ds.joinedRepositories {
+			qb.Join(j)
+		}
+	}
+	SQL, args, err := qb.BuildWithArgs()
+	if err != nil {
+		return 0, err
+	}
+	if ds.debug {
+		fmt.Println("Count SQL: ", SQL, args)
+	}
+	row, err := ds.adapter.QueryRowContext(ds.context(), SQL, args...)
+	if err != nil {
+		return 0, ds.translate(err)
+	}
+	var count int64
+	if err = row.Scan(&count); err != nil {
+		return 0, ds.translate(err)
+	}
+	return count, nil
+}
+
 /*
 FindByID - fetching Object by id. interface{} because id could be string or int
 */
@@ -269,20 +420,20 @@ func (ds *MySQL) FindByID(id interface{}) (interface{}, error) {
 	if len(data) > 0 {
 		return ds.mapItem(data[0])
 	}
-	return nil, vodka.NewError(400, "not_found", "Item not found")
+	return nil, vodka.NewNotFoundError("not_found", "Item not found")
 }
 
 // Exec - executes custom SQL and returns result
-func (ds *MySQL) Exec(SQL string) (interface{}, error) {
-	rows, err := ds.adapter.Query(SQL)
+func (ds *MySQL) Exec(SQL string, args ...interface{}) (interface{}, error) {
+	rows, err := ds.adapter.QueryContext(ds.context(), SQL, args...)
 	if err != nil {
 		fmt.Println("Error: ", err)
-		return nil, err
+		return nil, ds.translate(err)
 	}
 	defer rows.Close()
 	res, err := ds.buildResult(rows)
 	if err != nil {
-		return nil, err
+		return nil, ds.translate(err)
 	}
 	coll, err := ds.mapCollection(res)
 	if d, ok := coll.([]interface{}); ok {
@@ -293,21 +444,50 @@ func (ds *MySQL) Exec(SQL string) (interface{}, error) {
 	return coll, err
 }
 
-func (ds *MySQL) fetch(query QueryMap, params interface{}) ([]interface{}, error) {
+func (ds *MySQL) fetch(query QueryMap, params interface{}, conditions ...builders.Condition) ([]interface{}, error) {
+	mod, err := parseParams(params)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ds.fetchData(query, mod, conditions...)
+	if err != nil {
+		return nil, err
+	}
+	return ds.populate(data), nil
+}
+
+func (ds *MySQL) fetchData(query QueryMap, mod QueryModificator, conditions ...builders.Condition) ([]map[string]interface{}, error) {
 	qb := ds.adapter.Builder()
 	var fields []string
-	mod := parseParams(params)
 	if len(mod.fields) == 0 {
 		fields = lib.GetStructTags(reflect.ValueOf(ds.model).Elem(), "db", true)
 	} else {
 		fields = mod.fields
 	}
 	if mod.limit == 0 {
-		mod.limit = defaultLimit
+		mod.limit = DefaultLimit
+	}
+	// Keyset pagination: rows after cursor ordered by order columns and primary key
+	// Cursor is used only by FindAfter, Find pages with skip
+	if mod.keyset {
+		mod.orderBy = keysetOrder(mod.orderBy, ds.primaryKey())
+		mod.skip = 0
+		columns, desc, err := keysetColumns(mod.orderBy)
+		if err != nil {
+			return nil, err
+		}
+		if mod.cursor != "" {
+			values, err := decodeCursor(mod.cursor, len(columns))
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, builders.After(columns, values, desc))
+		}
 	}
 	qb.Select(fields).
 		From(ds.source).
 		Where(query).
+		WhereCond(conditions...).
 		Limit(mod.limit, mod.skip)
 
 	if len(ds.joinedRepositories) > 0 {
@@ -322,59 +502,54 @@ func (ds *MySQL) fetch(query QueryMap, params interface{}) ([]interface{}, error
 		}
 	}
 
-	SQL := qb.Build()
+	SQL, args, err := qb.BuildWithArgs()
+	if err != nil {
+		return nil, err
+	}
 	if ds.debug {
-		fmt.Println("Fetch SQL: ", SQL)
+		fmt.Println("Fetch SQL: ", SQL, args)
 	}
-	rows, err := ds.adapter.Query(SQL)
+	rows, err := ds.adapter.QueryContext(ds.context(), SQL, args...)
 	if err != nil {
 		fmt.Println("Error: ", err)
-		return nil, err
+		return nil, ds.translate(err)
 	}
 	defer rows.Close()
-	return ds.buildResult(rows)
+	data, err := scanRows(rows)
+	if err != nil {
+		return nil, ds.translate(err)
+	}
+	return data, nil
 }
 
 func (ds *MySQL) buildResult(rows *sql.Rows) ([]interface{}, error) {
-	var result []interface{}
-	i := 0
-	cols, _ := rows.Columns()
-	dest := make([]interface{}, len(cols))
-	rawResult := make([]interface{}, len(cols))
-
-	for c := range cols {
-		dest[c] = &rawResult[c]
+	data, err := scanRows(rows)
+	if err != nil {
+		return nil, err
 	}
+	return ds.populate(data), nil
+}
 
-	for rows.Next() {
-		data := make(map[string]interface{})
-		i++
-		if err := rows.Scan(dest...); err != nil {
-			fmt.Println("Error: ", err)
-			return nil, err
-		}
-		for key, v := range cols {
-			if a, ok := rawResult[key].([]byte); ok == true {
-				// data[v] = string(a)
-				f, e := strconv.ParseFloat(string(a), 64)
-				if e != nil {
-					data[v] = string(a)
-				} else {
-					data[v] = f
-				}
-			} else {
-				data[v] = rawResult[key]
-			}
-		}
+// populate - filling model with rows data. Rows are returned as is if model is not set
+func (ds *MySQL) populate(data []map[string]interface{}) []interface{} {
+	var result []interface{}
+	for _, row := range data {
 		if ds.model != nil {
 			m := reflect.ValueOf(ds.model)
-			a := populateStructByMap(m, data)
-			result = append(result, a)
+			result = append(result, populateStructByMap(m, row))
 		} else {
-			result = append(result, data)
+			result = append(result, row)
 		}
 	}
-	return result, nil
+	return result
+}
+
+// primaryKey - key column of model, "id" by default
+func (ds *MySQL) primaryKey() string {
+	if ds.key != "" {
+		return ds.key
+	}
+	return "id"
 }
 
 func (ds *MySQL) mapCollection(data []interface{}) (interface{}, error) {
@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/syndicatedb/vodka"
+	"github.com/syndicatedb/vodka/adapters"
+	"github.com/syndicatedb/vodka/builders"
+)
+
+type testOrder struct {
+	ID     int64  `db:"id" key:"true" json:"id"`
+	Status string `db:"status" json:"status"`
+}
+
+func TestPostgresWithTxRunsQueriesInTransaction(t *testing.T) {
+	db, adapter := newTestDB(t, builders.NewPostgres)
+	repo := NewPostgres(adapter, "orders", &testOrder{})
+
+	err := adapters.WithTx(adapter, func(tx adapters.Tx) error {
+		_, err := repo.WithTx(tx).DeleteByID(1)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q := db.last(t); !q.InTx {
+		t.Errorf("query is not run in transaction: %s", q.SQL)
+	}
+	if db.commits != 1 || db.rollbacks != 0 {
+		t.Errorf("expected commit, got %d commits and %d rollbacks", db.commits, db.rollbacks)
+	}
+
+	// Repository that is not bound keeps its adapter
+	if _, err := repo.DeleteByID(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q := db.last(t); q.InTx {
+		t.Errorf("query of original repository is run in transaction: %s", q.SQL)
+	}
+}
+
+func TestPostgresWithTxRollsBackOnError(t *testing.T) {
+	db, adapter := newTestDB(t, builders.NewPostgres)
+	repo := NewPostgres(adapter, "orders", &testOrder{})
+	errStock := errors.New("out of stock")
+
+	err := adapters.WithTx(adapter, func(tx adapters.Tx) error {
+		if _, err := repo.WithTx(tx).DeleteByID(1); err != nil {
+			return err
+		}
+		return errStock
+	})
+	if err != errStock {
+		t.Fatalf("expected error of function, got %v", err)
+	}
+	if db.commits != 0 || db.rollbacks != 1 {
+		t.Errorf("expected rollback, got %d commits and %d rollbacks", db.commits, db.rollbacks)
+	}
+}
+
+func TestPostgresCount(t *testing.T) {
+	db, adapter := newTestDB(t, builders.NewPostgres)
+	repo := NewPostgres(adapter, "orders", &testOrder{})
+	db.push([]string{"count"}, []driver.Value{int64(3)})
+
+	count, err := repo.Count(QueryMap{"status": "new"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3, got %d", count)
+	}
+	q := db.last(t)
+	if q.SQL != "SELECT COUNT(*) FROM  orders as t WHERE t.status=$1" {
+		t.Errorf("unexpected SQL: %s", q.SQL)
+	}
+	if !reflect.DeepEqual(q.Args, []interface{}{"new"}) {
+		t.Errorf("unexpected args: %v", q.Args)
+	}
+}
+
+func TestPostgresFindAfter(t *testing.T) {
+	db, adapter := newTestDB(t, builders.NewPostgres)
+	repo := NewPostgres(adapter, "orders", &testOrder{})
+	db.push([]string{"id", "status"},
+		[]driver.Value{int64(1), "new"},
+		[]driver.Value{int64(2), "new"},
+		[]driver.Value{int64(3), "new"},
+	)
+
+	items, cursor, err := repo.FindAfter(QueryMap{"status": "new"}, ParamsMap{"limit": 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list, ok := items.([]interface{}); !ok || len(list) != 2 {
+		t.Fatalf("expected 2 items, got %#v", items)
+	}
+	if cursor != encodeCursor(map[string]interface{}{"id": int64(2)}, []string{"id"}) {
+		t.Errorf("cursor doesn't point to last item: %q", cursor)
+	}
+	q := db.last(t)
+	if !strings.HasSuffix(q.SQL, "WHERE t.status=$1 ORDER BY t.id ASC LIMIT 3 OFFSET 0") {
+		t.Errorf("unexpected SQL: %s", q.SQL)
+	}
+
+	// Next page starts after cursor
+	if _, _, err = repo.FindAfter(QueryMap{"status": "new"}, ParamsMap{"limit": 2, "cursor": cursor}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	q = db.last(t)
+	if !strings.HasSuffix(q.SQL, "WHERE t.status=$1 AND (t.id) > ($2) ORDER BY t.id ASC LIMIT 3 OFFSET 0") {
+		t.Errorf("unexpected SQL: %s", q.SQL)
+	}
+	if !reflect.DeepEqual(q.Args, []interface{}{"new", "2"}) {
+		t.Errorf("unexpected args: %#v", q.Args)
+	}
+}
+
+func TestPostgresFindIgnoresCursor(t *testing.T) {
+	db, adapter := newTestDB(t, builders.NewPostgres)
+	repo := NewPostgres(adapter, "orders", &testOrder{})
+	cursor := encodeCursor(map[string]interface{}{"id": 2}, []string{"id"})
+
+	if _, err := repo.Find(QueryMap{}, ParamsMap{"limit": 10, "skip": 20, "cursor": cursor}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q := db.last(t); !strings.HasSuffix(q.SQL, "orders as t LIMIT 10 OFFSET 20") || len(q.Args) != 0 {
+		t.Errorf("unexpected query: %s %v", q.SQL, q.Args)
+	}
+}
+
+func TestPostgresFindAfterRejectsCursorOfOtherOrder(t *testing.T) {
+	_, adapter := newTestDB(t, builders.NewPostgres)
+	repo := NewPostgres(adapter, "orders", &testOrder{})
+	_, _, err := repo.FindAfter(QueryMap{}, ParamsMap{"orderBy": "id"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cursor := encodeCursor(map[string]interface{}{"id": 2}, []string{"id"})
+	_, _, err = repo.FindAfter(QueryMap{}, ParamsMap{"orderBy": "status,id", "cursor": cursor})
+	if !errors.Is(err, vodka.ErrBadRequest) {
+		t.Errorf("expected 400 error for cursor of other order, got %v", err)
+	}
+}
+
+func TestPostgresWithContextCancelsQueries(t *testing.T) {
+	db, adapter := newTestDB(t, builders.NewPostgres)
+	repo := NewPostgres(adapter, "orders", &testOrder{})
+	ctx, cancel := context.WithCancel(context.Background())
+	bound := repo.WithContext(ctx)
+
+	db.push([]string{"count"}, []driver.Value{int64(1)})
+	if _, err := bound.Count(QueryMap{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancel()
+	if _, err := bound.Count(QueryMap{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(db.queries) != 1 {
+		t.Errorf("query is run after cancel: %d queries", len(db.queries))
+	}
+
+	// Repository that is not bound keeps background context
+	db.push([]string{"count"}, []driver.Value{int64(1)})
+	if _, err := repo.Count(QueryMap{}); err != nil {
+		t.Errorf("unexpected error of original repository: %v", err)
+	}
+}
@@ -1,11 +1,15 @@
 package repositories
 
 import (
+	"context"
+
+	"github.com/syndicatedb/vodka/adapters"
 	"github.com/syndicatedb/vodka/builders"
 )
 
 const (
-	defaultLimit = 100
+	// DefaultLimit - limit for Find if it is not set in params
+	DefaultLimit = 100
 )
 
 /*
@@ -14,7 +18,13 @@ Recorder - Repository interface
 type Recorder interface {
 	Join(source, key, targetKey, joinType string, fields []string)
 	Find(QueryMap, ParamsMap) (interface{}, error)
+	// FindWhere - finding by conditions composed with builders.And/Or/Not
+	FindWhere(builders.Condition, ParamsMap) (interface{}, error)
 	FindByID(interface{}) (interface{}, error)
+	// FindAfter - keyset pagination, returns collection and next cursor
+	FindAfter(QueryMap, ParamsMap) (interface{}, string, error)
+	// Count - count of rows matching query
+	Count(QueryMap) (int64, error)
 	Create(interface{}) (interface{}, error)
 	Save(interface{}, ParamsMap) (interface{}, error)
 	Delete(QueryMap) (interface{}, error)
@@ -22,7 +32,13 @@ type Recorder interface {
 	Update(QueryMap, map[string]interface{}) (interface{}, error)
 	// SetMapper - setting mapper to build collection
 	SetMapper(mapper Mapper)
-	Exec(string) (interface{}, error)
+	// SetErrorTranslator - setting translator of driver errors, TranslateError by default
+	SetErrorTranslator(ErrorTranslator)
+	Exec(string, ...interface{}) (interface{}, error)
+	// WithTx - copy of repository that runs all queries in transaction
+	WithTx(adapters.Tx) Recorder
+	// WithContext - copy of repository that cancels queries when context is done
+	WithContext(context.Context) Recorder
 }
 
 /*
@@ -45,6 +61,8 @@ type QueryModificator struct {
 	orderBy              []builders.OrderParam
 	onConflictAction     string
 	OnConflictConstraint string
+	cursor               string
+	keyset               bool
 }
 
 // Mapper - mapping interface
@@ -0,0 +1,215 @@
+package vodka
+
+import (
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Resource actions that can be excluded in ResourceOptions
+const (
+	ActionFind       = "find"
+	ActionFindByID   = "findByID"
+	ActionCreate     = "create"
+	ActionSave       = "save"
+	ActionUpdateByID = "updateByID"
+	ActionDeleteByID = "deleteByID"
+)
+
+/*
+ResourceController - CRUD handlers mounted by Router.Resource. Implemented by base.Controller
+*/
+type ResourceController interface {
+	Find(*Context) (interface{}, error)
+	FindByID(*Context) (interface{}, error)
+	Create(*Context) (interface{}, error)
+	Save(*Context) (interface{}, error)
+	UpdateByID(*Context) (interface{}, error)
+	DeleteByID(*Context) (interface{}, error)
+}
+
+/*
+ResourceOptions - options of Router.Resource
+*/
+type ResourceOptions struct {
+	// Except - actions that are not mounted
+	Except []string
+	// Model - struct (or pointer) to derive validation rules for routes that have no rules in validation file
+	Model interface{}
+}
+
+/*
+Resource - mounting standard REST routes for controller:
+GET path, GET path/:id, POST path, PUT and PATCH path/:id, DELETE path/:id, POST path/save
+*/
+func (r *Router) Resource(path string, c ResourceController, opts ResourceOptions) {
+	path = strings.TrimSuffix(path, "/")
+	item := path + "/:id"
+	routes := []struct {
+		action  string
+		method  string
+		path    string
+		handler HandlerFunc
+	}{
+		{ActionFind, "GET", path, c.Find},
+		{ActionFindByID, "GET", item, c.FindByID},
+		{ActionCreate, "POST", path, c.Create},
+		{ActionSave, "POST", path + "/save", c.Save},
+		{ActionUpdateByID, "PUT", item, c.UpdateByID},
+		{ActionUpdateByID, "PATCH", item, c.UpdateByID},
+		{ActionDeleteByID, "DELETE", item, c.DeleteByID},
+	}
+	for _, rt := range routes {
+		if isExcluded(opts.Except, rt.action) {
+			continue
+		}
+		full := r.fullPath(rt.path)
+		var rules methodRules
+		var columns map[string]bool
+		if opts.Model != nil {
+			rules = modelRules(opts.Model, rt.action)
+			columns = modelColumns(opts.Model)
+		}
+		r.register(route{
+			method:  rt.method,
+			path:    full,
+			rules:   rules,
+			model:   opts.Model,
+			columns: columns,
+			action:  rt.action,
+		}, rt.handler)
+	}
+}
+
+func isExcluded(except []string, action string) bool {
+	for _, e := range except {
+		if e == action {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+modelRules - validation rules for resource action derived from model fields:
+column from `db` tag, request name from `json` tag and type from field type.
+Fields with `uuid` tag are generated by repository, so they are not expected in body
+*/
+func modelRules(model interface{}, action string) (rules methodRules) {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	fields := make(map[string]validation)
+	body := make(map[string]validation)
+	key := validation{InputType: "string", Required: true, Name: "id"}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		column := f.Tag.Get("db")
+		if column == "" || column == "-" {
+			continue
+		}
+		v := validation{InputType: fieldType(f.Type), Name: column}
+		v.Min, v.Max = typeRange(f.Type)
+		if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			v.Name = name
+		}
+		if f.Tag.Get("key") != "" && v.InputType != "" {
+			key.InputType = v.InputType
+		}
+		if v.InputType == "" {
+			continue
+		}
+		fields[column] = v
+		if f.Tag.Get("uuid") == "" {
+			body[column] = v
+		}
+	}
+	params := map[string]validation{"id": key}
+	switch action {
+	case ActionFind:
+		rules.Query = fields
+	case ActionFindByID, ActionDeleteByID:
+		rules.Params = params
+	case ActionCreate, ActionSave:
+		rules.Body = body
+	case ActionUpdateByID:
+		rules.Params = params
+		rules.Body = body
+	}
+	return
+}
+
+/*
+modelColumns - columns of model from `db` tags. Body of resource with policy "allow"
+keeps only unknown fields that are columns, so they can be written by repository
+*/
+func modelColumns(model interface{}) map[string]bool {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	columns := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return columns
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if column := t.Field(i).Tag.Get("db"); column != "" && column != "-" {
+			columns[column] = true
+		}
+	}
+	return columns
+}
+
+// fieldType - validation type for struct field. Empty for types that can't be validated
+func fieldType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return "int"
+	case reflect.Int64, reflect.Uint64:
+		return "int64"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float64"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Struct:
+		// time.Time is sent as RFC3339 string
+		if t.String() == "time.Time" {
+			return "string"
+		}
+	}
+	return ""
+}
+
+/*
+typeRange - min and max rules from range of integer type, so out of range value is 400
+and not error of decoding. Range of 64 bit types is wider than float64 keeps exactly, only
+unsigned ones get min
+*/
+func typeRange(t reflect.Type) (min, max *float64) {
+	bound := func(n float64) *float64 { return &n }
+	switch t.Kind() {
+	case reflect.Int8:
+		return bound(math.MinInt8), bound(math.MaxInt8)
+	case reflect.Int16:
+		return bound(math.MinInt16), bound(math.MaxInt16)
+	case reflect.Int32:
+		return bound(math.MinInt32), bound(math.MaxInt32)
+	case reflect.Uint8:
+		return bound(0), bound(math.MaxUint8)
+	case reflect.Uint16:
+		return bound(0), bound(math.MaxUint16)
+	case reflect.Uint32:
+		return bound(0), bound(math.MaxUint32)
+	case reflect.Uint, reflect.Uint64:
+		return bound(0), nil
+	}
+	return nil, nil
+}
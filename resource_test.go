@@ -0,0 +1,117 @@
+package vodka
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testController - controller that responds with action name
+type testController struct{}
+
+func (testController) Find(*Context) (interface{}, error)       { return ActionFind, nil }
+func (testController) FindByID(*Context) (interface{}, error)   { return ActionFindByID, nil }
+func (testController) Create(*Context) (interface{}, error)     { return ActionCreate, nil }
+func (testController) Save(*Context) (interface{}, error)       { return ActionSave, nil }
+func (testController) UpdateByID(*Context) (interface{}, error) { return ActionUpdateByID, nil }
+func (testController) DeleteByID(*Context) (interface{}, error) { return ActionDeleteByID, nil }
+
+type testUser struct {
+	ID    int64  `db:"id" key:"true" json:"id"`
+	UUID  string `db:"uuid" uuid:"true" json:"uuid"`
+	Name  string `db:"name" json:"name"`
+	Age   uint8  `db:"user_age" json:"age"`
+	Notes string `json:"notes"`
+	Tags  []int  `db:"tags"`
+}
+
+func TestResourceRoutes(t *testing.T) {
+	app := newTestApp()
+	app.Router.Group("/api").Resource("/users/", testController{}, ResourceOptions{Except: []string{ActionSave}})
+
+	want := []string{
+		"GET /api/users",
+		"GET /api/users/:id",
+		"POST /api/users",
+		"PUT /api/users/:id",
+		"PATCH /api/users/:id",
+		"DELETE /api/users/:id",
+	}
+	if !reflect.DeepEqual(app.Router.Routes, want) {
+		t.Errorf("routes:\n got %v\nwant %v", app.Router.Routes, want)
+	}
+	w := serve(app, httptest.NewRequest("GET", "/api/users/5", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"data":"findByID"`) {
+		t.Errorf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestModelRules(t *testing.T) {
+	zero, maxAge := 0.0, 255.0
+	name := validation{InputType: "string", Name: "name"}
+	age := validation{InputType: "int", Min: &zero, Max: &maxAge, Name: "age"}
+	key := map[string]validation{"id": {InputType: "int64", Required: true, Name: "id"}}
+
+	rules := modelRules(&testUser{}, ActionFind)
+	want := map[string]validation{
+		"id":       {InputType: "int64", Name: "id"},
+		"uuid":     {InputType: "string", Name: "uuid"},
+		"name":     name,
+		"user_age": age,
+	}
+	if !reflect.DeepEqual(rules.Query, want) {
+		t.Errorf("find query:\n got %+v\nwant %+v", rules.Query, want)
+	}
+
+	rules = modelRules(testUser{}, ActionUpdateByID)
+	if !reflect.DeepEqual(rules.Params, key) {
+		t.Errorf("params: got %+v, want %+v", rules.Params, key)
+	}
+	if _, ok := rules.Body["uuid"]; ok {
+		t.Error("generated uuid is expected in body")
+	}
+	if !reflect.DeepEqual(rules.Body["user_age"], age) || len(rules.Body) != 3 {
+		t.Errorf("unexpected body: %+v", rules.Body)
+	}
+
+	if rules := modelRules("users", ActionCreate); rules.Body != nil {
+		t.Errorf("rules for not struct model: %+v", rules)
+	}
+}
+
+func TestResourceIsValidatedByModel(t *testing.T) {
+	app := newTestApp()
+	app.Router.Resource("/users", testController{}, ResourceOptions{Model: testUser{}})
+
+	if w := serve(app, httptest.NewRequest("GET", "/users/abc", nil)); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for key that is not int, got %d", w.Code)
+	}
+	body := strings.NewReader(`{"name":"x","age":-1}`)
+	if w := serve(app, httptest.NewRequest("POST", "/users", body)); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for negative unsigned field, got %d", w.Code)
+	}
+	body = strings.NewReader(`{"name":"x","age":300}`)
+	if w := serve(app, httptest.NewRequest("POST", "/users", body)); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for value out of range of uint8, got %d", w.Code)
+	}
+	body = strings.NewReader(`{"name":"x","age":30}`)
+	if w := serve(app, httptest.NewRequest("POST", "/users", body)); w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestResourceRulesOfFileTakePrecedence(t *testing.T) {
+	app := newTestApp()
+	loadTestRules(t, app, `{
+		"/users/:id": {
+			"get": {"params": {"id": {"type": "string", "required": true}}}
+		}
+	}`)
+	app.Router.Resource("/users", testController{}, ResourceOptions{Model: testUser{}})
+
+	if w := serve(app, httptest.NewRequest("GET", "/users/abc", nil)); w.Code != http.StatusOK {
+		t.Errorf("expected 200 with rules of file, got %d: %s", w.Code, w.Body.String())
+	}
+}
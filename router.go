@@ -2,10 +2,14 @@ package vodka
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -20,76 +24,262 @@ type Handler interface {
 
 // Router — main struct for routing HTTP-requests
 type Router struct {
-	ID        string
-	Routes    []string
-	router    *httprouter.Router
-	validator *Validator
-	dispatch  func(*Context)
+	ID          string
+	Routes      []string
+	router      *httprouter.Router
+	validator   *Validator
+	dispatch    func(*Context)
+	sendError   func(*Context, error)
+	parent      *Router
+	prefix      string
+	middlewares []Middleware
+	hooks       []Hook
+	routes      []route
+	request     reflect.Type
+	timeout     time.Duration
+	maxBodySize int64
+}
+
+// route - registered route description for docs
+type route struct {
+	method string
+	path   string
+	// rules - rules derived from request type or model, rules of validation file take precedence
+	rules   methodRules
+	model   interface{}
+	columns map[string]bool
+	action  string
+	timeout time.Duration
 }
 
 // NewRouter - router constructor
 func NewRouter() *Router {
 	return &Router{
-		router: httprouter.New(),
+		router:      httprouter.New(),
+		maxBodySize: defaultMaxBodySize,
+	}
+}
+
+/*
+Group - sub-router for routes with prefix. Group middlewares and hooks are applied
+after application ones and only to group routes. Groups can be nested
+*/
+func (r *Router) Group(prefix string, mws ...Middleware) *Router {
+	return &Router{
+		parent:      r,
+		prefix:      r.fullPath(prefix),
+		middlewares: mws,
 	}
 }
 
+/*
+With - router with additional middlewares for single routes:
+router.With(auth, cache).GET("/users", handler).
+Route middlewares are applied after application and group ones in order they are passed
+*/
+func (r *Router) With(mws ...Middleware) *Router {
+	return r.Group("", mws...)
+}
+
+/*
+Request - router for routes that are validated by request type instead of rules in validation file:
+router.Request(CreateUser{}).POST("/users", handler).
+Rules are derived from struct tags (see Context.Bind), rules of validation file take precedence
+*/
+func (r *Router) Request(req interface{}) *Router {
+	g := r.Group("")
+	g.request = reflect.TypeOf(req)
+	return g
+}
+
+/*
+Timeout - router for routes that are cancelled after timeout: router.Timeout(5 * time.Second).GET(...).
+Context of request is done after timeout, option "timeout" of validation file takes precedence
+*/
+func (r *Router) Timeout(timeout time.Duration) *Router {
+	g := r.Group("")
+	g.timeout = timeout
+	return g
+}
+
+/*
+Use - setting middleware for routes of router (group) registered after this call
+*/
+func (r *Router) Use(m Middleware) {
+	r.middlewares = append(r.middlewares, m)
+}
+
+/*
+Hook - setting hook for routes of router (group) registered after this call
+*/
+func (r *Router) Hook(hook Hook) {
+	r.hooks = append(r.hooks, hook)
+}
+
 // SetValidator - setting validator for routes
 func (r *Router) SetValidator(v *Validator) {
-	r.validator = v
+	r.root().validator = v
+}
+
+/*
+SetMaxBodySize - max request body of routes in bytes, -1 for unlimited. Reading more is 413.
+Application.Server sets it from HTTPConfig.MaxBodySize
+*/
+func (r *Router) SetMaxBodySize(max int64) {
+	r.root().maxBodySize = max
 }
 
 // GetRouter - getting router method
 func (r *Router) GetRouter() *httprouter.Router {
-	return r.router
+	return r.root().router
 }
 
-func (r *Router) getValidationForPath(path, method string) methodRules {
+// findValidation - rules for path and method and flag that rules are defined in validation file
+func (r *Router) findValidation(path, method string) (methodRules, bool) {
 	method = strings.ToLower(method)
-	return r.validator.Rules[path][method]
+	validator := r.root().validator
+	if validator == nil {
+		return methodRules{}, false
+	}
+	return validator.find(path, method)
+}
+
+/*
+routeRules - current rules of validation file for route. Rules derived from
+request type or model are used if file has no rules for route
+*/
+func (r *Router) routeRules(rt route) methodRules {
+	if rules, ok := r.findValidation(rt.path, rt.method); ok {
+		return rules
+	}
+	return rt.rules
 }
 
 // GET - HTTP-method GET setting handler
 func (r *Router) GET(path string, h HandlerFunc) {
-	r.router.GET(path, r.handle(h, r.getValidationForPath(path, "GET")))
+	r.add("GET", path, h)
 }
 
 // POST - HTTP-method POST setting handler
 func (r *Router) POST(path string, h HandlerFunc) {
-	r.router.POST(path, r.handle(h, r.getValidationForPath(path, "POST")))
+	r.add("POST", path, h)
 }
 
 // PUT - HTTP-method PUT setting handler
 func (r *Router) PUT(path string, h HandlerFunc) {
-	r.router.PUT(path, r.handle(h, r.getValidationForPath(path, "PUT")))
+	r.add("PUT", path, h)
 }
 
 // DELETE - HTTP-method DELETE setting handler
 func (r *Router) DELETE(path string, h HandlerFunc) {
-	r.router.DELETE(path, r.handle(h, r.getValidationForPath(path, "DELETE")))
+	r.add("DELETE", path, h)
 }
 
 // PATCH - HTTP-method PATCH setting handler
 func (r *Router) PATCH(path string, h HandlerFunc) {
-	r.router.PATCH(path, r.handle(h, r.getValidationForPath(path, "PATCH")))
+	r.add("PATCH", path, h)
 }
 
 // OPTIONS - HTTP-method OPTIONS setting handler
 func (r *Router) OPTIONS(path string, h HandlerFunc) {
-	r.router.OPTIONS(path, r.handle(h, r.getValidationForPath(path, "OPTIONS")))
+	r.add("OPTIONS", path, h)
 }
 
 // HEAD - HTTP-method HEAD setting handler
 func (r *Router) HEAD(path string, h HandlerFunc) {
-	r.router.HEAD(path, r.handle(h, r.getValidationForPath(path, "HEAD")))
+	r.add("HEAD", path, h)
 }
 
-func (r *Router) handle(h HandlerFunc, v methodRules) httprouter.Handle {
-	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
-		body, err := parseBody(req)
-		if err != nil {
-			return
+// add - registering handler with full path. Validation rules are taken by full path on request
+func (r *Router) add(method, path string, h HandlerFunc) {
+	path = r.fullPath(path)
+	var rules methodRules
+	if request := r.requestType(); request != nil {
+		rules = requestBinding(request).rules
+	}
+	r.register(route{
+		method: method,
+		path:   path,
+		rules:  rules,
+	}, h)
+}
+
+// register - registering handler for route with full path
+func (r *Router) register(rt route, h HandlerFunc) {
+	root := r.root()
+	rt.timeout = r.routeTimeout()
+	root.Routes = append(root.Routes, rt.method+" "+rt.path)
+	root.routes = append(root.routes, rt)
+	root.router.Handle(rt.method, rt.path, r.handle(h, rt))
+}
+
+// timeoutsOver - routes with timeout of router or validation option longer than max
+func (r *Router) timeoutsOver(max time.Duration) (routes []string) {
+	root := r.root()
+	for _, rt := range root.routes {
+		timeout := optionTimeout(root.routeRules(rt).Options, rt.timeout)
+		if max > 0 && timeout > max {
+			routes = append(routes, fmt.Sprintf("%s %s (%v)", rt.method, rt.path, timeout))
 		}
+	}
+	return
+}
+
+// root - top router that holds httprouter, validator and dispatcher
+func (r *Router) root() *Router {
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
+// fullPath - path with prefixes of all parent groups
+func (r *Router) fullPath(path string) string {
+	if r.prefix == "" {
+		return path
+	}
+	if path == "" || path == "/" {
+		return r.prefix
+	}
+	return strings.TrimSuffix(r.prefix, "/") + path
+}
+
+// requestType - request type of router or its nearest parent
+func (r *Router) requestType() reflect.Type {
+	for ; r != nil; r = r.parent {
+		if r.request != nil {
+			return r.request
+		}
+	}
+	return nil
+}
+
+// routeTimeout - timeout of router or its nearest parent
+func (r *Router) routeTimeout() time.Duration {
+	for ; r != nil; r = r.parent {
+		if r.timeout > 0 {
+			return r.timeout
+		}
+	}
+	return 0
+}
+
+// chain - middlewares and hooks of router and its parents. Parents go first
+func (r *Router) chain() (mws []Middleware, hooks []Hook) {
+	if r.parent != nil {
+		mws, hooks = r.parent.chain()
+	}
+	mws = append(mws, r.middlewares...)
+	hooks = append(hooks, r.hooks...)
+	return
+}
+
+// handle - handler of route. Rules are taken on each request, so they can be reloaded
+func (r *Router) handle(h HandlerFunc, rt route) httprouter.Handle {
+	root := r.root()
+	mws, hooks := r.chain()
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		body, err := parseBody(w, req, root.maxBodySize)
 		ctx := Context{
 			Raw: RawContext{
 				Query:  parseQuery(req.URL.Query()),
@@ -103,17 +293,31 @@ func (r *Router) handle(h HandlerFunc, v methodRules) httprouter.Handle {
 			HandlerFunc: h,
 			Request:     req,
 			Writer:      w,
-			Validation:  v,
+			Validation:  root.routeRules(rt),
+			middlewares: mws,
+			hooks:       hooks,
+			timeout:     rt.timeout,
+			columns:     rt.columns,
 		}
-		r.dispatch(&ctx)
+		if err != nil {
+			root.sendError(&ctx, bodyError(err))
+			return
+		}
+		root.dispatch(&ctx)
 	}
 }
 
-func parseBody(req *http.Request) ([]byte, error) {
+// parseBody - reading body of JSON or form request. Body over max is error, max <= 0 is unlimited
+func parseBody(w http.ResponseWriter, req *http.Request, max int64) ([]byte, error) {
+	if max > 0 {
+		req.Body = http.MaxBytesReader(w, req.Body, max)
+	}
 	contentType := req.Header.Get("Content-Type")
 
 	if strings.Index(contentType, "multipart/form-data") > -1 {
-		req.ParseMultipartForm(1000000)
+		if err := req.ParseMultipartForm(1000000); err != nil {
+			return nil, err
+		}
 		d := make(map[string]interface{})
 		for key, v := range req.Form {
 			d[key] = v[0]
@@ -122,7 +326,9 @@ func parseBody(req *http.Request) ([]byte, error) {
 	}
 
 	if strings.Index(contentType, "x-www-form-urlencoded") > -1 {
-		req.ParseForm()
+		if err := req.ParseForm(); err != nil {
+			return nil, err
+		}
 		d := make(map[string]interface{})
 		for key, v := range req.Form {
 			d[key] = v[0]
@@ -132,6 +338,15 @@ func parseBody(req *http.Request) ([]byte, error) {
 	return ioutil.ReadAll(req.Body)
 }
 
+// bodyError - error response for body that can't be read
+func bodyError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return NewPayloadTooLargeError(ErrRequestTooLarge.Message, maxErr.Error())
+	}
+	return NewBadRequestError("body", err.Error())
+}
+
 func parseParams(ps httprouter.Params) (params KeyStorage) {
 	for _, param := range ps {
 		params.Set(param.Key, param.Value)
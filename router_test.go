@@ -0,0 +1,152 @@
+package vodka
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// loadTestRules - loading validation rules of application from JSON
+func loadTestRules(t *testing.T, app *Application, rules string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "validation.json")
+	if err := ioutil.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Validation(path); err != nil {
+		t.Fatalf("rules are not loaded: %v", err)
+	}
+}
+
+// trace - middleware that appends name to calls and calls next one
+func trace(calls *[]string, name string) Middleware {
+	return func(ctx *Context) (*Context, error) {
+		*calls = append(*calls, name)
+		ctx.Next(ctx)
+		return ctx, nil
+	}
+}
+
+func TestGroupPrefixes(t *testing.T) {
+	app := newTestApp()
+	api := app.Router.Group("/api/v1")
+	admin := api.Group("/admin/")
+	ok := func(ctx *Context) (interface{}, error) { return ctx.Request.URL.Path, nil }
+	api.GET("/users", ok)
+	admin.GET("/users", ok)
+	admin.GET("/", ok)
+
+	want := []string{"GET /api/v1/users", "GET /api/v1/admin/users", "GET /api/v1/admin/"}
+	if !reflect.DeepEqual(app.Router.Routes, want) {
+		t.Errorf("routes: got %v, want %v", app.Router.Routes, want)
+	}
+	for _, path := range []string{"/api/v1/users", "/api/v1/admin/users", "/api/v1/admin/"} {
+		if w := serve(app, httptest.NewRequest("GET", path, nil)); w.Code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestGroupMiddlewares(t *testing.T) {
+	app := newTestApp()
+	var calls []string
+	app.Use(trace(&calls, "app"))
+	api := app.Router.Group("/api", trace(&calls, "api"))
+	admin := api.Group("/admin", trace(&calls, "admin"))
+	admin.Use(trace(&calls, "admin use"))
+	handler := func(ctx *Context) (interface{}, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	}
+	app.Router.GET("/public", handler)
+	admin.GET("/users", handler)
+
+	serve(app, httptest.NewRequest("GET", "/public", nil))
+	if want := []string{"app", "handler"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("public route: got %v, want %v", calls, want)
+	}
+	calls = nil
+	serve(app, httptest.NewRequest("GET", "/api/admin/users", nil))
+	if want := []string{"app", "api", "admin", "admin use", "handler"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("group route: got %v, want %v", calls, want)
+	}
+}
+
+func TestGroupMiddlewareStopsRequest(t *testing.T) {
+	app := newTestApp()
+	admin := app.Router.Group("/admin", func(ctx *Context) (*Context, error) {
+		return ctx, NewUnathorizedError("unauthorized", nil)
+	})
+	admin.GET("/users", func(ctx *Context) (interface{}, error) {
+		t.Error("handler is called after middleware error")
+		return nil, nil
+	})
+	if w := serve(app, httptest.NewRequest("GET", "/admin/users", nil)); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
+
+func TestGroupRoutesAreValidatedByFullPath(t *testing.T) {
+	app := newTestApp()
+	loadTestRules(t, app, `{
+		"/api/users": {
+			"get": {"query": {"status": {"type": "string", "required": true}}}
+		}
+	}`)
+	app.Router.Group("/api").GET("/users", func(ctx *Context) (interface{}, error) {
+		return ctx.Query.Get("status"), nil
+	})
+
+	if w := serve(app, httptest.NewRequest("GET", "/api/users", nil)); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without required query, got %d", w.Code)
+	}
+	w := serve(app, httptest.NewRequest("GET", "/api/users?status=new", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"data":"new"`) {
+		t.Errorf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestWithMiddlewaresAreAppliedToSingleRoute(t *testing.T) {
+	app := newTestApp()
+	var calls []string
+	app.Use(trace(&calls, "app"))
+	api := app.Router.Group("/api", trace(&calls, "api"))
+	handler := func(ctx *Context) (interface{}, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	}
+	api.With(trace(&calls, "auth"), trace(&calls, "cache")).GET("/users", handler)
+	api.GET("/items", handler)
+
+	serve(app, httptest.NewRequest("GET", "/api/users", nil))
+	if want := []string{"app", "api", "auth", "cache", "handler"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("route with middlewares: got %v, want %v", calls, want)
+	}
+	calls = nil
+	serve(app, httptest.NewRequest("GET", "/api/items", nil))
+	if want := []string{"app", "api", "handler"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("other route of group: got %v, want %v", calls, want)
+	}
+}
+
+func TestWithMiddlewareStopsChain(t *testing.T) {
+	app := newTestApp()
+	var calls []string
+	limit := func(ctx *Context) (*Context, error) {
+		return ctx, NewTooManyRequestsError("rate_limit", nil)
+	}
+	app.Router.With(limit, trace(&calls, "cache")).GET("/users", func(ctx *Context) (interface{}, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	})
+	if w := serve(app, httptest.NewRequest("GET", "/users", nil)); w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", w.Code)
+	}
+	if len(calls) > 0 {
+		t.Errorf("chain is continued after error: %v", calls)
+	}
+}
@@ -0,0 +1,265 @@
+package vodka
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Rules of extended validation
+const (
+	RuleMin       = "min"
+	RuleMax       = "max"
+	RuleMinLength = "minLength"
+	RuleMaxLength = "maxLength"
+	RulePattern   = "pattern"
+	RuleEnum      = "enum"
+)
+
+// Formats for rule key "format"
+const (
+	FormatEmail    = "email"
+	FormatUUID     = "uuid"
+	FormatDateTime = "date-time"
+	FormatDate     = "date"
+	FormatURL      = "url"
+	FormatPhone    = "phone"
+)
+
+var (
+	uuidRegexp  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	phoneRegexp = regexp.MustCompile(`^\+?[1-9][0-9]{6,14}$`)
+	// phoneSeparators - characters allowed in phone number for readability: +1 (555) 123-45-67
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+)
+
+/*
+checkRules - checking typecasted value with extended rules of validation.
+Slice elements are checked one by one, length rules are checked for slice itself.
+Returns violated rule and error
+*/
+func checkRules(name string, value interface{}, val validation) (string, error) {
+	if rule, err := checkLength(name, value, val); err != nil {
+		return rule, err
+	}
+	for _, v := range elements(value) {
+		if rule, err := checkValue(name, v, val); err != nil {
+			return rule, err
+		}
+	}
+	return "", nil
+}
+
+// checkLength - checking length of string or slice with minLength and maxLength
+func checkLength(name string, value interface{}, val validation) (string, error) {
+	if val.MinLength == nil && val.MaxLength == nil {
+		return "", nil
+	}
+	length, ok := valueLength(value)
+	if !ok {
+		return "", nil
+	}
+	if val.MinLength != nil && length < *val.MinLength {
+		return RuleMinLength, fmt.Errorf("%s length must be at least %d", name, *val.MinLength)
+	}
+	if val.MaxLength != nil && length > *val.MaxLength {
+		return RuleMaxLength, fmt.Errorf("%s length must be at most %d", name, *val.MaxLength)
+	}
+	return "", nil
+}
+
+// checkValue - checking single value with numeric, pattern, enum and format rules
+func checkValue(name string, value interface{}, val validation) (string, error) {
+	if n, ok := toFloat(value); ok {
+		if val.Min != nil && n < *val.Min {
+			return RuleMin, fmt.Errorf("%s (%v) must be greater than or equal to %v", name, value, *val.Min)
+		}
+		if val.Max != nil && n > *val.Max {
+			return RuleMax, fmt.Errorf("%s (%v) must be less than or equal to %v", name, value, *val.Max)
+		}
+	}
+	if len(val.Enum) > 0 && !inEnum(value, val.Enum) {
+		return RuleEnum, fmt.Errorf("%s (%v) must be one of %v", name, value, val.Enum)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", nil
+	}
+	if val.Pattern != "" {
+		re := val.pattern
+		if re == nil {
+			var err error
+			if re, err = regexp.Compile(val.Pattern); err != nil {
+				return RulePattern, fmt.Errorf("%s: pattern %s is not valid: %v", name, val.Pattern, err)
+			}
+		}
+		if !re.MatchString(str) {
+			return RulePattern, fmt.Errorf("%s (%v) doesn't match pattern %s", name, value, val.Pattern)
+		}
+	}
+	if val.Format != "" {
+		if err := checkFormat(str, val.Format); err != nil {
+			return RuleFormat, fmt.Errorf("%s (%v) is not valid %s: %v", name, value, val.Format, err)
+		}
+	}
+	return "", nil
+}
+
+func checkFormat(value, format string) error {
+	switch format {
+	case FormatEmail:
+		addr, err := mail.ParseAddress(value)
+		if err != nil {
+			return err
+		}
+		if addr.Address != value {
+			return fmt.Errorf("address must not have name")
+		}
+	case FormatUUID:
+		if !uuidRegexp.MatchString(value) {
+			return fmt.Errorf("expected xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx")
+		}
+	case FormatDateTime:
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("expected RFC3339 date-time")
+		}
+	case FormatDate:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return fmt.Errorf("expected YYYY-MM-DD")
+		}
+	case FormatURL:
+		u, err := url.ParseRequestURI(value)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("scheme and host are required")
+		}
+	case FormatPhone:
+		if !phoneRegexp.MatchString(phoneSeparators.Replace(value)) {
+			return fmt.Errorf("expected international phone number")
+		}
+	default:
+		return fmt.Errorf("format is not supported")
+	}
+	return nil
+}
+
+/*
+compileRules - compiling patterns and checking formats of rules and nested rules on load,
+so invalid rule stops application instead of 400 on every request
+*/
+func compileRules(rules map[string]validation) error {
+	for _, key := range sortedRuleNames(rules) {
+		val := rules[key]
+		if err := compileRule(key, &val); err != nil {
+			return err
+		}
+		rules[key] = val
+	}
+	return nil
+}
+
+func compileRule(name string, val *validation) error {
+	if val.Pattern != "" {
+		re, err := regexp.Compile(val.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: pattern %s is not valid: %v", name, val.Pattern, err)
+		}
+		val.pattern = re
+	}
+	switch val.Format {
+	case "", FormatEmail, FormatUUID, FormatDateTime, FormatDate, FormatURL, FormatPhone:
+	default:
+		return fmt.Errorf("%s: format %s is not supported", name, val.Format)
+	}
+	if err := compileRules(val.Properties); err != nil {
+		return fmt.Errorf("%s.%v", name, err)
+	}
+	if val.Items != nil {
+		items := *val.Items
+		if err := compileRule(name+"[]", &items); err != nil {
+			return err
+		}
+		val.Items = &items
+	}
+	return nil
+}
+
+// inEnum - comparing numbers as float64 because enum values are decoded from JSON
+func inEnum(value interface{}, enum []interface{}) bool {
+	n, isNumber := toFloat(value)
+	for _, e := range enum {
+		if en, ok := toFloat(e); ok && isNumber {
+			if en == n {
+				return true
+			}
+			continue
+		}
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// valueLength - length of string in characters or length of slice
+func valueLength(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case string:
+		return len([]rune(v)), true
+	case []string:
+		return len(v), true
+	case []int64:
+		return len(v), true
+	case []float64:
+		return len(v), true
+	case []interface{}:
+		return len(v), true
+	}
+	return 0, false
+}
+
+// elements - elements of slice or value itself
+func elements(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []string:
+		var els []interface{}
+		for _, el := range v {
+			els = append(els, el)
+		}
+		return els
+	case []int64:
+		var els []interface{}
+		for _, el := range v {
+			els = append(els, el)
+		}
+		return els
+	case []float64:
+		var els []interface{}
+		for _, el := range v {
+			els = append(els, el)
+		}
+		return els
+	case []interface{}:
+		return v
+	}
+	return []interface{}{value}
+}
@@ -0,0 +1,110 @@
+package vodka
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func floatRule(v float64) *float64 { return &v }
+func intRule(v int) *int           { return &v }
+
+func TestCheckRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		val   validation
+		rule  string
+	}{
+		{"min", 3, validation{Min: floatRule(5)}, RuleMin},
+		{"min ok", 5, validation{Min: floatRule(5)}, ""},
+		{"max", 10.5, validation{Max: floatRule(10)}, RuleMax},
+		{"min length in runes", "пр", validation{MinLength: intRule(3)}, RuleMinLength},
+		{"max length", "abcd", validation{MaxLength: intRule(3)}, RuleMaxLength},
+		{"slice length", []int64{1, 2, 3}, validation{MaxLength: intRule(2)}, RuleMaxLength},
+		{"slice elements", []int64{1, 20}, validation{Max: floatRule(10)}, RuleMax},
+		{"pattern", "ab1", validation{Pattern: `^[a-z]+$`}, RulePattern},
+		{"pattern ok", "abc", validation{Pattern: `^[a-z]+$`}, ""},
+		{"enum", "closed", validation{Enum: []interface{}{"new", "paid"}}, RuleEnum},
+		{"number enum", int64(2), validation{Enum: []interface{}{1.0, 2.0}}, ""},
+		{"format", "not-email", validation{Format: FormatEmail}, RuleFormat},
+	}
+	for _, tt := range tests {
+		rule, err := checkRules("field", tt.value, tt.val)
+		if rule != tt.rule || (err != nil) != (tt.rule != "") {
+			t.Errorf("%s: got %q, %v, want %q", tt.name, rule, err, tt.rule)
+		}
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		format, value string
+		valid         bool
+	}{
+		{FormatEmail, "user@example.com", true},
+		{FormatEmail, "User <user@example.com>", false},
+		{FormatUUID, "3f2504e0-4f89-11d3-9a0c-0305e82c3301", true},
+		{FormatUUID, "3f2504e0", false},
+		{FormatDateTime, "2020-01-02T15:04:05Z", true},
+		{FormatDateTime, "2020-01-02", false},
+		{FormatDate, "2020-01-02", true},
+		{FormatDate, "02.01.2020", false},
+		{FormatURL, "https://example.com/a", true},
+		{FormatURL, "/a/b", false},
+		{FormatPhone, "+1 (555) 123-45-67", true},
+		{FormatPhone, "12", false},
+		{"ipv4", "127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if err := checkFormat(tt.value, tt.format); (err == nil) != tt.valid {
+			t.Errorf("%s %q: valid %v, got error %v", tt.format, tt.value, tt.valid, err)
+		}
+	}
+}
+
+func TestCompileRules(t *testing.T) {
+	rules := map[string]validation{
+		"code": {Pattern: `^[A-Z]{3}$`},
+		"items": {InputType: TypeArray, Items: &validation{
+			InputType:  TypeObject,
+			Properties: map[string]validation{"sku": {Pattern: `^\d+$`}},
+		}},
+	}
+	if err := compileRules(rules); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules["code"].pattern == nil || rules["items"].Items.Properties["sku"].pattern == nil {
+		t.Error("patterns are not compiled")
+	}
+
+	tests := []struct {
+		rules map[string]validation
+		err   string
+	}{
+		{map[string]validation{"code": {Pattern: `[`}}, "code: pattern [ is not valid"},
+		{map[string]validation{"ip": {Format: "ipv4"}}, "ip: format ipv4 is not supported"},
+		{map[string]validation{"customer": {InputType: TypeObject, Properties: map[string]validation{
+			"email": {Format: "mail"},
+		}}}, "customer.email: format mail is not supported"},
+		{map[string]validation{"tags": {InputType: TypeArray, Items: &validation{Pattern: `(`}}}, "tags[]: pattern ( is not valid"},
+	}
+	for _, tt := range tests {
+		if err := compileRules(tt.rules); err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("expected %q, got %v", tt.err, err)
+		}
+	}
+}
+
+func TestValidationFailsOnInvalidRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "validation.json")
+	rules := `{"/users": {"post": {"body": {"name": {"type": "string", "pattern": "("}}}}}`
+	if err := ioutil.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := newTestApp().Validation(path)
+	if err == nil || !strings.Contains(err.Error(), "name: pattern ( is not valid") {
+		t.Errorf("expected error of pattern, got %v", err)
+	}
+}
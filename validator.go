@@ -2,32 +2,58 @@ package vodka
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
-	lib "github.com/niklucky/go-lib"
+	"github.com/syndicatedb/vodka/builders"
 )
 
 // Validator — struct that contains validation rules
 type Validator struct {
-	Rules map[string]routeRules
+	Rules  map[string]routeRules
+	source string
+	types  customTypes
+	mu     sync.RWMutex
 }
 
-func (v *Validator) loadRules(fileName string) error {
-	fileData, err := lib.ReadFile(fileName)
-	if err != nil {
-		return err
-	}
-	err = json.Unmarshal(fileData, &v.Rules)
+// loadRules - loading rules from JSON or YAML file or directory of files
+func (v *Validator) loadRules(path string) error {
+	rules, err := loadRulesPath(path)
 	if err != nil {
 		return err
 	}
+	v.setRules(path, rules)
 	return nil
 }
 
+// setRules - replacing rules. Requests that are in progress keep old rules
+func (v *Validator) setRules(source string, rules map[string]routeRules) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.source = source
+	v.Rules = rules
+}
+
+func (v *Validator) getSource() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.source
+}
+
+// find - rules for path and method
+func (v *Validator) find(path, method string) (methodRules, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	rules, ok := v.Rules[path][method]
+	return rules, ok
+}
+
 type routeRules map[string]methodRules
 
 type methodRules struct {
@@ -35,16 +61,117 @@ type methodRules struct {
 	Query   map[string]validation  `json:"query"`
 	Body    map[string]validation  `json:"body"`
 	Options map[string]interface{} `json:"options"`
+	// Rules - cross-field rules checked after fields are valid
+	Rules []fieldRule `json:"rules"`
 }
 
 type validation struct {
-	InputType string `json:"type"`
-	Required  bool   `json:"required"`
-	Name      string `json:"name"`
+	InputType string        `json:"type"`
+	Required  bool          `json:"required"`
+	Name      string        `json:"name"`
+	Min       *float64      `json:"min"`
+	Max       *float64      `json:"max"`
+	MinLength *int          `json:"minLength"`
+	MaxLength *int          `json:"maxLength"`
+	Pattern   string        `json:"pattern"`
+	Enum      []interface{} `json:"enum"`
+	Format    string        `json:"format"`
+	// Properties - rules of nested fields for type "object"
+	Properties map[string]validation `json:"properties"`
+	// Items - rule of elements for type "array"
+	Items *validation `json:"items"`
+	// Default - value of absent field
+	Default interface{} `json:"default"`
+	// pattern - compiled Pattern
+	pattern *regexp.Regexp
+}
+
+// Types of nested values
+const (
+	TypeObject = "object"
+	TypeArray  = "array"
+)
+
+// Locations of validated fields
+const (
+	LocationParams = "params"
+	LocationQuery  = "query"
+	LocationBody   = "body"
+)
+
+// Violated rules
+const (
+	RuleRequired = "required"
+	RuleType     = "type"
+	RuleFormat   = "format"
+	RuleUnknown  = "unknown"
+)
+
+// OptionUnknownFields - route option with policy of fields that are not described in rules
+const OptionUnknownFields = "unknownFields"
+
+// OptionTimeout - route option with timeout of request: seconds (5) or duration ("500ms")
+const OptionTimeout = "timeout"
+
+// Policies of unknown fields
+const (
+	// UnknownStrip - unknown fields are removed (default)
+	UnknownStrip = "strip"
+	// UnknownAllow - unknown fields are passed as is
+	UnknownAllow = "allow"
+	// UnknownReject - request with unknown fields is rejected with 400
+	UnknownReject = "reject"
+)
+
+/*
+FieldError - validation error of single request field
+*/
+type FieldError struct {
+	Location string      `json:"location"`
+	Field    string      `json:"field"`
+	Expected string      `json:"expected,omitempty"`
+	Rule     string      `json:"rule"`
+	Value    interface{} `json:"value,omitempty"`
+	Message  string      `json:"message"`
 }
 
-func (e *Application) validate(ctx *Context) (err error) {
-	var errs []string
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+/*
+ValidationErrors - all validation errors of request. Sent in Error.Info
+*/
+type ValidationErrors []FieldError
+
+// Error - text of errors grouped by location: "Params: id is not defined, Body: ..."
+func (errs ValidationErrors) Error() string {
+	var parts []string
+	labels := map[string]string{LocationParams: "Params", LocationQuery: "Query", LocationBody: "Body"}
+	for _, location := range []string{LocationParams, LocationQuery, LocationBody} {
+		var msgs []string
+		for _, e := range errs {
+			if e.Location == location {
+				msgs = append(msgs, e.Message)
+			}
+		}
+		if len(msgs) > 0 {
+			parts = append(parts, labels[location]+": "+strings.Join(msgs, ", "))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+/*
+validationError - 400 Error with message "validation" and ValidationErrors in Info.
+Error() of it has text of all errors
+*/
+func validationError(errs ValidationErrors) error {
+	return WrapError(ErrorBadRequestCode, errs, "validation", errs)
+}
+
+func (e *Application) validate(ctx *Context) error {
+	var errs ValidationErrors
 	if isDebug {
 		log.Printf("Validation rules: %+v", ctx.Validation)
 	}
@@ -55,48 +182,64 @@ func (e *Application) validate(ctx *Context) (err error) {
 		}
 	}
 	if v.Params != nil {
-		ctx.Params, err = validateMap(v.Params, ctx.Raw.Params)
-		if err != nil {
-			errs = append(errs, "Params: "+err.Error())
-		}
+		var fieldErrs ValidationErrors
+		ctx.Params, fieldErrs = validateMap(ctx.types, LocationParams, v.Params, ctx.Raw.Params, UnknownStrip)
+		errs = append(errs, fieldErrs...)
 	}
 	if v.Query != nil {
-		ctx.Query, err = validateMap(v.Query, ctx.Raw.Query)
-		if err != nil {
-			errs = append(errs, "Query: "+err.Error())
-		}
+		var fieldErrs ValidationErrors
+		ctx.Query, fieldErrs = validateMap(ctx.types, LocationQuery, v.Query, ctx.Raw.Query, UnknownStrip)
+		errs = append(errs, fieldErrs...)
+		errs = append(errs, validateFilters(ctx.types, v.Query, ctx.Raw.Query, &ctx.Query)...)
 	}
 	if v.Body != nil {
-		var body map[string]interface{}
-		json.Unmarshal(ctx.Raw.Body, &body)
-		var b KeyStorage
-		for key, v := range body {
-			b.Set(key, v)
-		}
-		ctx.Body, err = validateMap(v.Body, b)
-		if err != nil {
-			errs = append(errs, "Params: "+err.Error())
+		b, fieldErrs := parseJSONBody(ctx.Raw.Body)
+		errs = append(errs, fieldErrs...)
+		policy := unknownFieldsPolicy(v.Options)
+		ctx.Body, fieldErrs = validateMap(ctx.types, LocationBody, v.Body, b, policy)
+		errs = append(errs, fieldErrs...)
+		if policy == UnknownAllow && ctx.columns != nil {
+			ctx.Body = modelFields(ctx.Body, v.Body, ctx.columns)
 		}
 	}
 	ctx.Options.Set("params", getParamsFromQuery(ctx.Raw.Query))
 
+	if len(errs) == 0 {
+		errs = validateFieldRules(v.Rules, v, ctx)
+	}
 	if len(errs) > 0 {
-		return errors.New(strings.Join(errs, ", "))
+		return validationError(errs)
 	}
 	return nil
 }
 
+// parseJSONBody - raw body as JSON object
+func parseJSONBody(raw []byte) (b KeyStorage, errs ValidationErrors) {
+	if len(raw) == 0 {
+		return
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		errs = append(errs, FieldError{
+			Location: LocationBody,
+			Rule:     RuleFormat,
+			Expected: "object",
+			Message:  "body is not valid JSON object",
+		})
+	}
+	for key, v := range body {
+		b.Set(key, v)
+	}
+	return
+}
+
 func getParamsFromQuery(q KeyStorage) interface{} {
 	p := make(map[string]interface{})
-	if q.Get("__limit") != nil {
-		if limit, err := strconv.Atoi(q.GetString("__limit")); err == nil {
-			p["limit"] = limit
-		}
+	if limit, err := q.ToInt("__limit"); err == nil {
+		p["limit"] = limit
 	}
-	if q.Get("__skip") != nil {
-		if skip, err := strconv.Atoi(q.GetString("__skip")); err == nil {
-			p["skip"] = skip
-		}
+	if skip, err := q.ToInt("__skip"); err == nil {
+		p["skip"] = skip
 	}
 	if q.Get("__orderBy") != nil {
 		p["orderBy"] = q.GetString("__orderBy")
@@ -104,6 +247,9 @@ func getParamsFromQuery(q KeyStorage) interface{} {
 	if q.Get("__order") != nil {
 		p["order"] = q.GetString("__order")
 	}
+	if q.Get("__cursor") != nil {
+		p["cursor"] = q.GetString("__cursor")
+	}
 	if q.Get("__conflictAction") != nil {
 		p["onConflictAction"] = q.GetString("__conflictAction")
 	}
@@ -113,43 +259,305 @@ func getParamsFromQuery(q KeyStorage) interface{} {
 	return p
 }
 
-func validateMap(vm map[string]validation, dv KeyStorage) (ks KeyStorage, err error) {
-	var errs []string
-	var typeErr error
-	for key, val := range vm {
-		var v interface{}
-		var name = key
-		if val.Name != "" {
-			name = val.Name
+/*
+validateMap - validating fields by rules. Fields that are not described in rules
+are handled by policy: strip, allow or reject
+*/
+func validateMap(types customTypes, location string, vm map[string]validation, dv KeyStorage, policy string) (ks KeyStorage, errs ValidationErrors) {
+	fields, errs := validateFields(types, location, "", vm, dv.Map(), policy)
+	for key, v := range fields {
+		ks.Set(key, v)
+	}
+	return
+}
+
+// routeTimeout - timeout from route options, timeout of router otherwise
+func routeTimeout(ctx *Context) time.Duration {
+	return optionTimeout(ctx.Validation.Options, ctx.timeout)
+}
+
+// optionTimeout - timeout of option, fallback if option is not set
+func optionTimeout(options map[string]interface{}, fallback time.Duration) time.Duration {
+	switch timeout := options[OptionTimeout].(type) {
+	case float64:
+		return time.Duration(timeout * float64(time.Second))
+	case string:
+		if d, err := time.ParseDuration(timeout); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+/*
+modelFields - body with fields of rules and unknown fields that are model columns.
+Other unknown fields are removed, so they don't get into INSERT and UPDATE
+*/
+func modelFields(body KeyStorage, rules map[string]validation, columns map[string]bool) (ks KeyStorage) {
+	for key, v := range body.Map() {
+		if _, ok := rules[key]; ok || columns[key] {
+			ks.Set(key, v)
+		}
+	}
+	return
+}
+
+// unknownFieldsPolicy - policy of unknown fields from route options
+func unknownFieldsPolicy(options map[string]interface{}) string {
+	switch policy := options[OptionUnknownFields]; policy {
+	case UnknownAllow, UnknownReject:
+		return policy.(string)
+	}
+	return UnknownStrip
+}
+
+/*
+validateValue - typecasting value and checking its rules.
+Objects and arrays are validated recursively, path of nested field is
+used as field name: items[2].amount
+*/
+func validateValue(types customTypes, location, path string, value interface{}, val validation, policy string) (interface{}, ValidationErrors) {
+	switch val.InputType {
+	case TypeObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, ValidationErrors{typeError(location, path, value, val.InputType, formatError(path, value, val.InputType))}
+		}
+		if val.Properties == nil {
+			return obj, nil
+		}
+		return validateObject(types, location, path, obj, val.Properties, policy)
+	case TypeArray:
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, ValidationErrors{typeError(location, path, value, val.InputType, formatError(path, value, val.InputType))}
+		}
+		if rule, err := checkLength(path, list, val); err != nil {
+			return nil, ValidationErrors{ruleError(location, path, value, val, rule, err)}
+		}
+		if val.Items == nil {
+			return list, nil
+		}
+		var errs ValidationErrors
+		res := make([]interface{}, len(list))
+		for i, el := range list {
+			elPath := fmt.Sprintf("%s[%d]", path, i)
+			if el == nil {
+				if val.Items.Required {
+					errs = append(errs, requiredError(location, elPath, *val.Items))
+				}
+				continue
+			}
+			v, fieldErrs := validateValue(types, location, elPath, el, *val.Items, policy)
+			errs = append(errs, fieldErrs...)
+			res[i] = v
+		}
+		if len(errs) > 0 {
+			return nil, errs
+		}
+		return res, nil
+	}
+	v, err := validateType(types, path, value, val.InputType)
+	if _, ok := err.(*strconv.NumError); ok {
+		err = formatError(path, value, val.InputType)
+	}
+	if err != nil {
+		return nil, ValidationErrors{typeError(location, path, value, val.InputType, err)}
+	}
+	if rule, err := checkRules(path, v, val); err != nil {
+		return nil, ValidationErrors{ruleError(location, path, value, val, rule, err)}
+	}
+	return v, nil
+}
+
+// validateObject - validating fields of nested object with policy of unknown fields
+func validateObject(types customTypes, location, path string, obj map[string]interface{}, properties map[string]validation, policy string) (interface{}, ValidationErrors) {
+	res, errs := validateFields(types, location, path, properties, obj, policy)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return res, nil
+}
+
+/*
+validateFields - validating fields of object by rules. Result is keyed by rule keys,
+absent fields get default values, unknown fields are handled by policy
+*/
+func validateFields(types customTypes, location, path string, rules map[string]validation, data map[string]interface{}, policy string) (map[string]interface{}, ValidationErrors) {
+	var errs ValidationErrors
+	res := make(map[string]interface{})
+	known := make(map[string]bool)
+	// Sorted to get errors in the same order
+	for _, key := range sortedRuleNames(rules) {
+		val := rules[key]
+		name := ruleName(key, val)
+		known[name] = true
+		field := fieldPath(path, name)
+		value := data[name]
+		if value == nil {
+			value = val.Default
 		}
-		value := dv.Get(name)
-		// if isDebug {
-		// 	fmt.Printf("Value: %s - %+v\n", name, value)
-		// }
 		if value == nil {
 			if val.Required {
-				errs = append(errs, name+" is not defined")
+				errs = append(errs, requiredError(location, field, val))
 			}
 			continue
 		}
-
-		v, typeErr = validateType(name, value, val.InputType)
-		if typeErr != nil {
-			errs = append(errs, typeErr.Error())
+		v, fieldErrs := validateValue(types, location, field, value, val, policy)
+		if len(fieldErrs) > 0 {
+			errs = append(errs, fieldErrs...)
 			continue
 		}
-		ks.Set(key, v)
+		res[key] = v
 	}
-	if len(errs) > 0 {
-		return ks, errors.New(strings.Join(errs, ", "))
+	if policy == UnknownStrip {
+		return res, errs
+	}
+	names := make([]string, 0, len(data))
+	for name := range data {
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if policy == UnknownAllow {
+			res[name] = data[name]
+			continue
+		}
+		errs = append(errs, FieldError{
+			Location: location,
+			Field:    fieldPath(path, name),
+			Rule:     RuleUnknown,
+			Value:    data[name],
+			Message:  fieldPath(path, name) + " is not allowed",
+		})
+	}
+	return res, errs
+}
+
+// fieldPath - path of nested field: customer.email
+func fieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func requiredError(location, name string, val validation) FieldError {
+	return FieldError{
+		Location: location,
+		Field:    name,
+		Expected: val.InputType,
+		Rule:     RuleRequired,
+		Message:  name + " is not defined",
+	}
+}
+
+// ruleError - field error for value that violates extended rule
+func ruleError(location, name string, value interface{}, val validation, rule string, err error) FieldError {
+	return FieldError{
+		Location: location,
+		Field:    name,
+		Expected: val.InputType,
+		Rule:     rule,
+		Value:    value,
+		Message:  err.Error(),
+	}
+}
+
+// typeError - field error for value that can't be typecasted
+func typeError(location, name string, value interface{}, t string, err error) FieldError {
+	return FieldError{
+		Location: location,
+		Field:    name,
+		Expected: t,
+		Rule:     RuleType,
+		Value:    value,
+		Message:  err.Error(),
+	}
+}
+
+/*
+validateFilters - validating query filters: field__operator=value (amount__gte=10, status__in=a,b).
+Only fields that have query rules can be filtered. Values are typecasted by rule type
+and saved as key__operator
+*/
+func validateFilters(types customTypes, vm map[string]validation, dv KeyStorage, ks *KeyStorage) (errs ValidationErrors) {
+	params := make([]string, 0, len(dv.Map()))
+	for param := range dv.Map() {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+	for _, param := range params {
+		value := dv.Get(param)
+		field, operator := builders.SplitFilter(param)
+		if operator == "" {
+			continue
+		}
+		key, rule, ok := findRule(vm, field)
+		if !ok {
+			continue
+		}
+		v, err := validateFilter(types, param, value, rule.InputType, operator)
+		if err != nil {
+			errs = append(errs, typeError(LocationQuery, param, value, rule.InputType, err))
+			continue
+		}
+		ks.Set(key+builders.FilterSeparator+operator, v)
 	}
 	return
 }
 
-func validateType(key string, value interface{}, t string) (res interface{}, err error) {
+// findRule - finding rule by request field name
+func findRule(vm map[string]validation, field string) (string, validation, bool) {
+	for key, val := range vm {
+		name := key
+		if val.Name != "" {
+			name = val.Name
+		}
+		if name == field {
+			return key, val, true
+		}
+	}
+	return "", validation{}, false
+}
+
+func validateFilter(types customTypes, key string, value interface{}, t, operator string) (interface{}, error) {
+	elemType := strings.TrimPrefix(t, "[]")
+	switch operator {
+	case builders.FilterIsNull:
+		return validateType(types, key, value, "bool")
+	case builders.FilterLike, builders.FilterILike:
+		return validateType(types, key, value, "string")
+	case builders.FilterIn, builders.FilterNotIn, builders.FilterBetween:
+		str, ok := value.(string)
+		if !ok {
+			return nil, formatError(key, value, t)
+		}
+		var list []interface{}
+		for _, el := range strings.Split(str, ",") {
+			v, err := validateType(types, key, el, elemType)
+			if err != nil {
+				return nil, fmt.Errorf("%s (%v): slice element %s is not %s", key, value, el, elemType)
+			}
+			list = append(list, v)
+		}
+		if operator == builders.FilterBetween && len(list) != 2 {
+			return nil, fmt.Errorf("%s (%v): between expects two values", key, value)
+		}
+		return list, nil
+	}
+	return validateType(types, key, value, elemType)
+}
+
+func validateType(types customTypes, key string, value interface{}, t string) (res interface{}, err error) {
 	if value == nil {
 		return value, nil
 	}
+	if fn, ok := types[t]; ok {
+		return validateCustom(key, value, t, fn)
+	}
 	// if isDebug {
 	// 	fmt.Printf("%T\n", value)
 	// }
@@ -258,6 +666,12 @@ func validateType(key string, value interface{}, t string) (res interface{}, err
 			res = strings.Split(v, ",")
 			return
 		}
+	case []interface{}:
+		if strings.HasPrefix(t, "[]") {
+			return validateSlice(types, key, v, t)
+		}
+	case map[string]interface{}:
+		return nil, formatError(key, value, t)
 	case bool:
 		if v == true {
 			res = true
@@ -284,6 +698,40 @@ func validateType(key string, value interface{}, t string) (res interface{}, err
 	return nil, formatError(key, value, t)
 }
 
+// validateSlice - typecasting elements of JSON array to slice type: []int64, []float64, []string
+func validateSlice(types customTypes, key string, list []interface{}, t string) (interface{}, error) {
+	elemType := t[2:]
+	var res []interface{}
+	for _, el := range list {
+		v, err := validateType(types, key, el, elemType)
+		if err != nil || v == nil {
+			return nil, fmt.Errorf("%s (%v): slice element %v is not %s", key, list, el, elemType)
+		}
+		res = append(res, v)
+	}
+	switch elemType {
+	case "int64":
+		si := make([]int64, len(res))
+		for i, v := range res {
+			si[i] = v.(int64)
+		}
+		return si, nil
+	case "float64":
+		sf := make([]float64, len(res))
+		for i, v := range res {
+			sf[i] = v.(float64)
+		}
+		return sf, nil
+	case "string":
+		ss := make([]string, len(res))
+		for i, v := range res {
+			ss[i] = v.(string)
+		}
+		return ss, nil
+	}
+	return res, nil
+}
+
 func formatError(key string, value interface{}, t string) error {
 	return fmt.Errorf("%s (%v) type is not valid (expected %s)", key, value, t)
 }
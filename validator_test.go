@@ -0,0 +1,321 @@
+package vodka
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateFilters(t *testing.T) {
+	rules := map[string]validation{
+		"amount": {InputType: "int"},
+		"status": {InputType: "string"},
+		"userId": {InputType: "int64", Name: "user"},
+	}
+	var raw KeyStorage
+	raw.Set("amount__gte", "10")
+	raw.Set("amount__between", "1,5")
+	raw.Set("status__in", "new,paid")
+	raw.Set("status__isnull", "true")
+	raw.Set("user__ne", "7")
+	raw.Set("other__gt", "1")
+	raw.Set("amount", "3")
+
+	var query KeyStorage
+	errs := validateFilters(nil, rules, raw, &query)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := map[string]interface{}{
+		"amount__gte":     10,
+		"amount__between": []interface{}{1, 5},
+		"status__in":      []interface{}{"new", "paid"},
+		"status__isnull":  true,
+		"userId__ne":      int64(7),
+	}
+	if !reflect.DeepEqual(query.Map(), want) {
+		t.Errorf("filters:\n got %#v\nwant %#v", query.Map(), want)
+	}
+}
+
+func TestValidateFiltersErrors(t *testing.T) {
+	rules := map[string]validation{"amount": {InputType: "int"}}
+	tests := []struct{ key, value string }{
+		{"amount__gte", "ten"},
+		{"amount__in", "1,x"},
+		{"amount__between", "1"},
+	}
+	for _, tt := range tests {
+		var raw, query KeyStorage
+		raw.Set(tt.key, tt.value)
+		errs := validateFilters(nil, rules, raw, &query)
+		if len(errs) != 1 || errs[0].Field != tt.key || errs[0].Location != LocationQuery {
+			t.Errorf("%s=%s: expected query error of field, got %v", tt.key, tt.value, errs)
+		}
+		if query.Has(tt.key) {
+			t.Errorf("%s=%s: invalid filter is set", tt.key, tt.value)
+		}
+	}
+}
+
+func TestValidationErrorsText(t *testing.T) {
+	errs := ValidationErrors{
+		{Location: LocationBody, Field: "name", Message: "name is not defined"},
+		{Location: LocationParams, Field: "id", Message: "id is not defined"},
+		{Location: LocationBody, Field: "age", Message: "age (x) type is not valid (expected int)"},
+	}
+	want := "Params: id is not defined, Body: name is not defined, age (x) type is not valid (expected int)"
+	if errs.Error() != want {
+		t.Errorf("got %q, want %q", errs.Error(), want)
+	}
+}
+
+func TestValidationErrorsResponse(t *testing.T) {
+	app := newTestApp()
+	loadTestRules(t, app, `{
+		"/users/:id": {
+			"put": {
+				"params": {"id": {"type": "int64", "required": true}},
+				"query": {"notify": {"type": "bool"}},
+				"body": {
+					"name": {"type": "string", "required": true},
+					"age": {"type": "int", "name": "userAge"}
+				}
+			}
+		}
+	}`)
+	app.Router.PUT("/users/:id", func(ctx *Context) (interface{}, error) {
+		t.Error("handler is called for invalid request")
+		return nil, nil
+	})
+
+	w := serve(app, httptest.NewRequest("PUT", "/users/x?notify=maybe", strings.NewReader(`{"userAge":"old"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	var res struct {
+		Error struct {
+			Code    string       `json:"code"`
+			Message string       `json:"message"`
+			Info    []FieldError `json:"info"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("response is not JSON: %v", err)
+	}
+	want := []FieldError{
+		{Location: LocationParams, Field: "id", Expected: "int64", Rule: RuleType, Value: "x", Message: "id (x) type is not valid (expected int64)"},
+		{Location: LocationQuery, Field: "notify", Expected: "bool", Rule: RuleType, Value: "maybe", Message: "notify (maybe) type is not valid (expected bool)"},
+		{Location: LocationBody, Field: "userAge", Expected: "int", Rule: RuleType, Value: "old", Message: "userAge (old) type is not valid (expected int)"},
+		{Location: LocationBody, Field: "name", Expected: "string", Rule: RuleRequired, Message: "name is not defined"},
+	}
+	if !reflect.DeepEqual(res.Error.Info, want) {
+		t.Errorf("info:\n got %+v\nwant %+v", res.Error.Info, want)
+	}
+	if res.Error.Code != ErrBadRequest.Code || res.Error.Message != "validation" {
+		t.Errorf("unexpected error: %s %q", res.Error.Code, res.Error.Message)
+	}
+}
+
+func TestValidationErrorKeepsMessage(t *testing.T) {
+	errs := ValidationErrors{{Location: LocationParams, Field: "id", Message: "id is not defined"}}
+	err := validationError(errs)
+	var e Error
+	if !errors.As(err, &e) || e.Status() != ErrorBadRequestCode || e.Message != "validation" {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	var fieldErrs ValidationErrors
+	if !errors.As(err, &fieldErrs) || !reflect.DeepEqual(fieldErrs, errs) {
+		t.Errorf("field errors are not wrapped: %v", fieldErrs)
+	}
+	if err.Error() != "validation: Params: id is not defined" {
+		t.Errorf("unexpected text: %q", err.Error())
+	}
+}
+
+func TestInvalidJSONBody(t *testing.T) {
+	_, errs := parseJSONBody([]byte(`{"name":`))
+	if len(errs) != 1 || errs[0].Location != LocationBody || errs[0].Rule != RuleFormat {
+		t.Errorf("unexpected errors: %+v", errs)
+	}
+}
+
+func TestValidateNestedFields(t *testing.T) {
+	rules := map[string]validation{
+		"customer": {InputType: TypeObject, Required: true, Properties: map[string]validation{
+			"email": {InputType: "string", Required: true, Format: FormatEmail},
+		}},
+		"items": {InputType: TypeArray, MinLength: intRule(1), Items: &validation{
+			InputType: TypeObject,
+			Properties: map[string]validation{
+				"sku":    {InputType: "string", Required: true},
+				"amount": {InputType: "int", Min: floatRule(1)},
+			},
+		}},
+	}
+	var body KeyStorage
+	body.Set("customer", map[string]interface{}{"email": "user@example.com", "name": "x"})
+	body.Set("items", []interface{}{
+		map[string]interface{}{"sku": "a", "amount": 2.0},
+		map[string]interface{}{"sku": "b"},
+	})
+
+	ks, errs := validateMap(nil, LocationBody, rules, body, UnknownStrip)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := map[string]interface{}{
+		"customer": map[string]interface{}{"email": "user@example.com"},
+		"items": []interface{}{
+			map[string]interface{}{"sku": "a", "amount": 2},
+			map[string]interface{}{"sku": "b"},
+		},
+	}
+	if !reflect.DeepEqual(ks.Map(), want) {
+		t.Errorf("body:\n got %#v\nwant %#v", ks.Map(), want)
+	}
+}
+
+func TestValidateNestedFieldsErrors(t *testing.T) {
+	rules := map[string]validation{
+		"customer": {InputType: TypeObject, Properties: map[string]validation{
+			"email": {InputType: "string", Required: true},
+		}},
+		"items": {InputType: TypeArray, Items: &validation{
+			InputType:  TypeObject,
+			Properties: map[string]validation{"amount": {InputType: "int", Min: floatRule(1)}},
+		}},
+		"tags": {InputType: TypeArray, MaxLength: intRule(1)},
+	}
+	var body KeyStorage
+	body.Set("customer", map[string]interface{}{})
+	body.Set("items", []interface{}{
+		map[string]interface{}{"amount": 1.0},
+		"x",
+		map[string]interface{}{"amount": 0.0},
+	})
+	body.Set("tags", []interface{}{"a", "b"})
+
+	_, errs := validateMap(nil, LocationBody, rules, body, UnknownStrip)
+	want := []struct{ field, rule string }{
+		{"customer.email", RuleRequired},
+		{"items[1]", RuleType},
+		{"items[2].amount", RuleMin},
+		{"tags", RuleMaxLength},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), errs)
+	}
+	for i, w := range want {
+		if errs[i].Field != w.field || errs[i].Rule != w.rule {
+			t.Errorf("error %d: got %s %s, want %s %s", i, errs[i].Field, errs[i].Rule, w.field, w.rule)
+		}
+	}
+
+	body.Set("customer", "user@example.com")
+	if _, errs = validateMap(nil, LocationBody, rules, body, UnknownStrip); errs[0].Field != "customer" || errs[0].Rule != RuleType {
+		t.Errorf("expected type error of object, got %v", errs)
+	}
+}
+
+func TestValidateDefaults(t *testing.T) {
+	rules := map[string]validation{
+		"status": {InputType: "string", Required: true, Default: "new"},
+		"amount": {InputType: "int", Default: 1.0},
+		"note":   {InputType: "string"},
+	}
+	ks, errs := validateMap(nil, LocationBody, rules, KeyStorage{}, UnknownStrip)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := map[string]interface{}{"status": "new", "amount": 1}
+	if !reflect.DeepEqual(ks.Map(), want) {
+		t.Errorf("got %#v, want %#v", ks.Map(), want)
+	}
+}
+
+func TestUnknownFieldsPolicies(t *testing.T) {
+	rules := map[string]validation{
+		"name":     {InputType: "string"},
+		"customer": {InputType: TypeObject, Properties: map[string]validation{"email": {InputType: "string"}}},
+	}
+	var body KeyStorage
+	body.Set("name", "x")
+	body.Set("role", "admin")
+	body.Set("customer", map[string]interface{}{"email": "a@b.c", "vip": true})
+
+	ks, errs := validateMap(nil, LocationBody, rules, body, UnknownStrip)
+	want := map[string]interface{}{"name": "x", "customer": map[string]interface{}{"email": "a@b.c"}}
+	if len(errs) > 0 || !reflect.DeepEqual(ks.Map(), want) {
+		t.Errorf("strip: got %#v %v", ks.Map(), errs)
+	}
+
+	ks, errs = validateMap(nil, LocationBody, rules, body, UnknownAllow)
+	want = map[string]interface{}{"name": "x", "role": "admin", "customer": map[string]interface{}{"email": "a@b.c", "vip": true}}
+	if len(errs) > 0 || !reflect.DeepEqual(ks.Map(), want) {
+		t.Errorf("allow: got %#v %v", ks.Map(), errs)
+	}
+
+	_, errs = validateMap(nil, LocationBody, rules, body, UnknownReject)
+	if len(errs) != 2 || errs[0].Field != "customer.vip" || errs[1].Field != "role" || errs[1].Rule != RuleUnknown {
+		t.Errorf("reject: unexpected errors %+v", errs)
+	}
+}
+
+func TestUnknownFieldsOption(t *testing.T) {
+	tests := []struct {
+		options map[string]interface{}
+		policy  string
+	}{
+		{nil, UnknownStrip},
+		{map[string]interface{}{OptionUnknownFields: "allow"}, UnknownAllow},
+		{map[string]interface{}{OptionUnknownFields: "reject"}, UnknownReject},
+		{map[string]interface{}{OptionUnknownFields: "keep"}, UnknownStrip},
+		{map[string]interface{}{OptionUnknownFields: true}, UnknownStrip},
+	}
+	for _, tt := range tests {
+		if policy := unknownFieldsPolicy(tt.options); policy != tt.policy {
+			t.Errorf("%v: got %s, want %s", tt.options, policy, tt.policy)
+		}
+	}
+}
+
+func TestResourceAllowsOnlyModelColumns(t *testing.T) {
+	app := newTestApp()
+	loadTestRules(t, app, `{
+		"/users": {
+			"post": {
+				"body": {"name": {"type": "string"}},
+				"options": {"unknownFields": "allow"}
+			}
+		}
+	}`)
+	var body map[string]interface{}
+	app.Router.Resource("/users", resourceFunc(func(ctx *Context) (interface{}, error) {
+		body = ctx.Body.Map()
+		return nil, nil
+	}), ResourceOptions{Model: testUser{}})
+
+	w := serve(app, httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"x","tags":[1],"password":"secret"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	want := map[string]interface{}{"name": "x", "tags": []interface{}{1.0}}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body: got %#v, want %#v", body, want)
+	}
+}
+
+// resourceFunc - controller with the same handler for all actions
+type resourceFunc HandlerFunc
+
+func (f resourceFunc) Find(ctx *Context) (interface{}, error)       { return f(ctx) }
+func (f resourceFunc) FindByID(ctx *Context) (interface{}, error)   { return f(ctx) }
+func (f resourceFunc) Create(ctx *Context) (interface{}, error)     { return f(ctx) }
+func (f resourceFunc) Save(ctx *Context) (interface{}, error)       { return f(ctx) }
+func (f resourceFunc) UpdateByID(ctx *Context) (interface{}, error) { return f(ctx) }
+func (f resourceFunc) DeleteByID(ctx *Context) (interface{}, error) { return f(ctx) }